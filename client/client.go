@@ -1,10 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -153,3 +156,88 @@ func (client HTTPClient) SaveUserTasks(habits habit.TaskList) error {
 
 	return nil
 }
+
+// habitRequest sends request for single habit with given id
+// as logged in user and returns response status code and body.
+func (client HTTPClient) habitRequest(method, id string, body []byte) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout*time.Second)
+	defer cancel()
+
+	token, _, err := client.login()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to login user to access habit: %w", err)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx,
+		method,
+		client.Address+"/user/habits/"+url.PathEscape(id),
+		bytes.NewReader(body))
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error during %s call for habit %s: %w", method, id, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read habit %s response: %w", id, err)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+// LoadUserTask returns user habit with given id.
+func (client HTTPClient) LoadUserTask(id string) (habit.Task, error) {
+	code, body, err := client.habitRequest(http.MethodGet, id, nil)
+	if err != nil {
+		return habit.Task{}, err
+	}
+
+	if code != http.StatusOK {
+		return habit.Task{}, fmt.Errorf("get /user/habits/%s should return %d while it returned %d", id, http.StatusOK, code)
+	}
+
+	task := habit.Task{}
+	if err := json.Unmarshal(body, &task); err != nil {
+		return habit.Task{}, fmt.Errorf("failed to decode habit %s: %w", id, err)
+	}
+
+	return task, nil
+}
+
+// SaveUserTask saves single habit to remote server replacing
+// habit with the same id or adding it as a new one.
+func (client HTTPClient) SaveUserTask(task habit.Task) error {
+	b, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal habit %s: %w", task.ID, err)
+	}
+
+	code, _, err := client.habitRequest(http.MethodPut, task.ID, b)
+	if err != nil {
+		return err
+	}
+
+	if code != http.StatusOK && code != http.StatusCreated {
+		return fmt.Errorf("put /user/habits/%s should return %d or %d while it returned %d",
+			task.ID, http.StatusOK, http.StatusCreated, code)
+	}
+
+	return nil
+}
+
+// DeleteUserTask removes habit with given id from remote server.
+func (client HTTPClient) DeleteUserTask(id string) error {
+	code, _, err := client.habitRequest(http.MethodDelete, id, nil)
+	if err != nil {
+		return err
+	}
+
+	if code != http.StatusNoContent {
+		return fmt.Errorf("delete /user/habits/%s should return %d while it returned %d", id, http.StatusNoContent, code)
+	}
+
+	return nil
+}
@@ -0,0 +1,519 @@
+//nolint:forbidigo //prints for command line client are not debug statements
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+// runCommand runs non interactive habitui command with given arguments
+// and returns program exit code.
+func runCommand(name string, args []string, tasksStore *store) int {
+	switch name {
+	case "doctor":
+		return runDoctor(args, tasksStore)
+	case "notes":
+		return runNotes(args, tasksStore)
+	case "list":
+		return runList(args, tasksStore)
+	case "done", "undo":
+		return runDone(name, args, tasksStore)
+	case "add":
+		return runAdd(args, tasksStore)
+	case "rm":
+		return runRemove(args, tasksStore)
+	case "stats":
+		return runStats(args, tasksStore)
+	case "status":
+		return runStatus(args, tasksStore)
+	case "restore":
+		return runRestore(args, tasksStore)
+	default:
+		fmt.Printf("unknown command %q\n", name)
+
+		return 1
+	}
+}
+
+// runDoctor checks whether statistics stored in tasks file agree
+// with completion history and optionally repairs the file.
+func runDoctor(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fix := flags.Bool("fix", false, "rewrite tasks file with statistics recomputed from history")
+	_ = flags.Parse(args)
+
+	inputFile, outputFile := tasksStore.inputFile, tasksStore.outputFile
+	if inputFile == "" {
+		fmt.Println("no tasks file found")
+
+		return 1
+	}
+
+	unlock, err := lockFile(outputFile)
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+	defer unlock()
+
+	bytes, err := os.ReadFile(inputFile)
+	if err != nil {
+		fmt.Printf("failed to open tasks file '%s': %v\n", inputFile, err)
+
+		return 1
+	}
+
+	tasks, issues, err := habit.JSONCheckTasks(bytes)
+	if err != nil {
+		fmt.Println("failed to check tasks:", err)
+
+		return 1
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("%s: %d tasks, no issues found\n", inputFile, len(tasks))
+
+		return 0
+	}
+
+	if !*fix {
+		fmt.Printf("%s: %d issues found, run with -fix to repair\n", inputFile, len(issues))
+
+		return 1
+	}
+
+	if err := habit.JSONSaveTasks(outputFile, tasks); err != nil {
+		fmt.Println("failed to save repaired tasks:", err)
+
+		return 1
+	}
+
+	fmt.Printf("%s: %d issues repaired\n", outputFile, len(issues))
+
+	return 0
+}
+
+// runNotes prints completion notes which contain given query.
+func runNotes(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("notes", flag.ExitOnError)
+	mood := flags.Int("mood", 0, "show only notes with given mood from 1 to 5")
+	_ = flags.Parse(args)
+
+	tasks, err := tasksStore.load()
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+
+	for _, match := range tasks.SearchNotes(strings.Join(flags.Args(), " "), *mood) {
+		fmt.Println(match)
+	}
+
+	return 0
+}
+
+// taskSummary is task state printed by list command.
+type taskSummary struct {
+	ID            string
+	Name          string
+	Category      string   `json:",omitempty"`
+	Tags          []string `json:",omitempty"`
+	Schedule      string
+	Archived      bool `json:",omitempty"`
+	Paused        bool `json:",omitempty"`
+	DoneToday     bool
+	CurrentStrike int
+	BestStrike    int
+}
+
+// periodSummary is task completion over a week, month or year.
+type periodSummary struct {
+	Completed int
+	Target    int
+}
+
+// taskStatistics is task state printed by stats command.
+type taskStatistics struct {
+	taskSummary
+	Week            periodSummary
+	Month           periodSummary
+	Year            periodSummary
+	MonthRate       float64
+	MonthBestStrike int
+	YearBestStrike  int
+}
+
+func summarize(task habit.Task) taskSummary {
+	return taskSummary{
+		ID:            task.ID,
+		Name:          task.Name,
+		Category:      task.Category,
+		Tags:          task.Tags,
+		Schedule:      task.Schedule.String(),
+		Archived:      task.Archived,
+		Paused:        task.IsPaused(),
+		DoneToday:     task.WasCompletedToday(),
+		CurrentStrike: task.CurrentStrike(),
+		BestStrike:    task.BestStrike(),
+	}
+}
+
+func statistics(task habit.Task) taskStatistics {
+	week, month, year := task.AllCompletion()
+	weekTarget, monthTarget, yearTarget := task.AllTarget()
+
+	return taskStatistics{
+		taskSummary:     summarize(task),
+		Week:            periodSummary{Completed: week, Target: weekTarget},
+		Month:           periodSummary{Completed: month, Target: monthTarget},
+		Year:            periodSummary{Completed: year, Target: yearTarget},
+		MonthRate:       task.CurrentMonthCompletionRate(),
+		MonthBestStrike: task.CurrentMonthBestStrike(),
+		YearBestStrike:  task.CurrentYearBestStrike(),
+	}
+}
+
+// printJSON prints value as indented JSON and returns command exit code.
+func printJSON(value any) int {
+	bytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Println("failed to marshal output:", err)
+
+		return 1
+	}
+
+	fmt.Println(string(bytes))
+
+	return 0
+}
+
+// parseArgs parses flags given before or after positional
+// arguments and returns positional arguments.
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	positional := []string{}
+
+	for {
+		_ = flags.Parse(args)
+		if args = flags.Args(); len(args) == 0 {
+			return positional
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// loadTask loads tasks and finds task named by positional arguments.
+func loadTask(command string, args []string, tasksStore *store) (habit.TaskList, int, bool) {
+	if len(args) == 0 {
+		fmt.Printf("%s: habit name or id is required\n", command)
+
+		return nil, -1, false
+	}
+
+	tasks, err := tasksStore.load()
+	if err != nil {
+		fmt.Println(err)
+
+		return nil, -1, false
+	}
+
+	idx, err := tasks.Find(strings.Join(args, " "))
+	if err != nil {
+		fmt.Printf("%s: %v\n", command, err)
+
+		return nil, -1, false
+	}
+
+	return tasks, idx, true
+}
+
+// runList prints tasks with their today completion and strike.
+func runList(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print tasks as JSON")
+	all := flags.Bool("all", false, "list archived tasks too")
+	_ = parseArgs(flags, args)
+
+	tasks, err := tasksStore.load()
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+
+	if !*all {
+		tasks = tasks.Active()
+	}
+
+	summaries := make([]taskSummary, 0, len(tasks))
+	for _, task := range tasks {
+		summaries = append(summaries, summarize(task))
+	}
+
+	if *asJSON {
+		return printJSON(summaries)
+	}
+
+	for idx, task := range tasks {
+		mark := " "
+
+		switch {
+		case task.Kind == habit.TaskKindQuantity:
+			mark = fmt.Sprintf("%d/%d", task.AmountToday(), task.Target)
+		case summaries[idx].DoneToday:
+			mark = "x"
+		case task.IsSkipped(task.Now()):
+			mark = "-"
+		}
+
+		fmt.Printf("[%s] %s (%s, strike %d)\n", mark, task.Name, task.Schedule, summaries[idx].CurrentStrike)
+	}
+
+	return 0
+}
+
+// runDone marks task completed or removes its completion at given date.
+func runDone(command string, args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	date := flags.String("date", "", "day in YYYY-MM-DD format instead of today")
+	asJSON := flags.Bool("json", false, "print task statistics as JSON")
+
+	tasks, idx, ok := loadTask(command, parseArgs(flags, args), tasksStore)
+	if !ok {
+		return 1
+	}
+
+	task := &tasks[idx]
+	day := task.Now()
+
+	if *date != "" {
+		var err error
+		if day, err = time.ParseInLocation(time.DateOnly, *date, habit.Location()); err != nil {
+			fmt.Printf("%s: invalid date %q expected YYYY-MM-DD\n", command, *date)
+
+			return 1
+		}
+
+		if day.After(task.Now()) {
+			fmt.Printf("%s: date %s is in the future\n", command, *date)
+
+			return 1
+		}
+	}
+
+	done := task.WasCompletedAt(day.Date())
+
+	switch {
+	case command == "done" && !done:
+		task.MakeCompletedAt(day)
+	case command == "undo" && done:
+		task.MakeUnCompletedAt(day)
+	default:
+		if !*asJSON {
+			fmt.Printf("%s: nothing to change for %q at %s\n", command, task.Name, day.Format(time.DateOnly))
+		}
+	}
+
+	if _, err := tasksStore.Save(tasks); err != nil {
+		fmt.Println("failed to save tasks:", err)
+
+		return 1
+	}
+
+	if *asJSON {
+		return printJSON(statistics(*task))
+	}
+
+	fmt.Printf("%s: strike %d\n", task.Name, task.CurrentStrike())
+
+	return 0
+}
+
+// runAdd adds new task.
+func runAdd(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("add", flag.ExitOnError)
+	description := flags.String("description", "", "task description")
+	schedule := flags.String("schedule", "daily", "how often task should be done e.g. 3/week or mon,wed,fri")
+	target := flags.String("target", "", "daily target e.g. 8 glasses")
+	labels := flags.String("labels", "", "category and tags e.g. health: morning, outdoor")
+	quit := flags.Bool("quit", false, "track habit to quit instead of habit to do")
+	asJSON := flags.Bool("json", false, "print added task as JSON")
+
+	name := strings.Join(parseArgs(flags, args), " ")
+	if name == "" {
+		fmt.Println("add: habit name is required")
+
+		return 1
+	}
+
+	task := habit.NewTask(name, *description)
+
+	var err error
+	if task.Schedule, err = habit.ParseSchedule(*schedule); err != nil {
+		fmt.Println("add:", err)
+
+		return 1
+	}
+
+	if err := task.SetQuit(*quit); err != nil {
+		fmt.Println("add:", err)
+
+		return 1
+	}
+
+	if *target != "" {
+		amount, unit, err := habit.ParseTarget(*target)
+		if err == nil {
+			err = task.SetTarget(amount, unit)
+		}
+
+		if err != nil {
+			fmt.Println("add:", err)
+
+			return 1
+		}
+	}
+
+	task.Category, task.Tags = habit.ParseLabels(*labels)
+
+	tasks, err := tasksStore.load()
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+
+	if _, err := tasksStore.Save(append(tasks, tasks.WithVacations(task))); err != nil {
+		fmt.Println("failed to save tasks:", err)
+
+		return 1
+	}
+
+	if *asJSON {
+		return printJSON(summarize(task))
+	}
+
+	fmt.Printf("added %q with id %s\n", task.Name, task.ID)
+
+	return 0
+}
+
+// runRemove deletes task with its whole history.
+func runRemove(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("rm", flag.ExitOnError)
+
+	tasks, idx, ok := loadTask("rm", parseArgs(flags, args), tasksStore)
+	if !ok {
+		return 1
+	}
+
+	name := tasks[idx].Name
+
+	if _, err := tasksStore.Save(slices.Delete(tasks, idx, idx+1)); err != nil {
+		fmt.Println("failed to save tasks:", err)
+
+		return 1
+	}
+
+	fmt.Printf("removed %q\n", name)
+
+	return 0
+}
+
+// runStats prints completion and strike statistics of task.
+func runStats(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print statistics as JSON")
+
+	tasks, idx, ok := loadTask("stats", parseArgs(flags, args), tasksStore)
+	if !ok {
+		return 1
+	}
+
+	stats := statistics(tasks[idx])
+	if *asJSON {
+		return printJSON(stats)
+	}
+
+	fmt.Printf("%s (%s)\n", stats.Name, stats.Schedule)
+	fmt.Printf("  Done today: %t\n", stats.DoneToday)
+	fmt.Printf("  Strike: current %d, best monthly %d, best yearly %d, best ever %d\n",
+		stats.CurrentStrike, stats.MonthBestStrike, stats.YearBestStrike, stats.BestStrike)
+	fmt.Printf("  This week: %d / %d\n", stats.Week.Completed, stats.Week.Target)
+	fmt.Printf("  This month: %d / %d (%.0f%%)\n", stats.Month.Completed, stats.Month.Target, stats.MonthRate*100) //nolint:gomnd
+	fmt.Printf("  This year: %d / %d\n", stats.Year.Completed, stats.Year.Target)
+
+	return 0
+}
+
+// runRestore lists backups of tasks file or restores backup with given number.
+func runRestore(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	positional := parseArgs(flags, args)
+
+	if tasksStore.remote != nil {
+		fmt.Println("restore: backups are kept only for local tasks file")
+
+		return 1
+	}
+
+	backups, err := habit.Backups(tasksStore.outputFile)
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+
+	if len(positional) == 0 {
+		if len(backups) == 0 {
+			fmt.Printf("no backups of %s\n", tasksStore.outputFile)
+		}
+
+		for idx, backup := range backups {
+			fmt.Printf("%d. %s  %s\n", idx+1, backup.Time.Format(time.DateTime), backup.Path)
+		}
+
+		return 0
+	}
+
+	number, err := strconv.Atoi(positional[0])
+	if err != nil || number < 1 || number > len(backups) {
+		fmt.Printf("restore: backup number should be between 1 and %d\n", len(backups))
+
+		return 1
+	}
+
+	unlock, err := lockFile(tasksStore.outputFile)
+	if err != nil {
+		fmt.Println("restore:", err)
+
+		return 1
+	}
+	defer unlock()
+
+	if err := habit.RestoreBackup(tasksStore.outputFile, backups[number-1]); err != nil {
+		fmt.Println("restore:", err)
+
+		return 1
+	}
+
+	fmt.Printf("restored %s from backup of %s, replaced file was kept as backup\n",
+		tasksStore.outputFile, backups[number-1].Time.Format(time.DateTime))
+
+	return 0
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args       []string
+		positional []string
+		date       string
+		asJSON     bool
+	}{
+		{[]string{"run"}, []string{"run"}, "", false},
+		{[]string{"-date", "2024-01-02", "run", "5k"}, []string{"run", "5k"}, "2024-01-02", false},
+		{[]string{"run", "5k", "-json", "-date=2024-01-02"}, []string{"run", "5k"}, "2024-01-02", true},
+		{[]string{"run", "-json", "5k"}, []string{"run", "5k"}, "", true},
+		{[]string{"--", "-json"}, []string{"-json"}, "", false},
+	}
+
+	for _, test := range tests {
+		flags := flag.NewFlagSet("done", flag.ContinueOnError)
+		date := flags.String("date", "", "")
+		asJSON := flags.Bool("json", false, "")
+
+		positional := parseArgs(flags, test.args)
+		if !slices.Equal(positional, test.positional) || *date != test.date || *asJSON != test.asJSON {
+			t.Errorf("parseArgs(%q) = %q with date %q and json %t, expected %q with date %q and json %t",
+				test.args, positional, *date, *asJSON, test.positional, test.date, test.asJSON)
+		}
+	}
+}
+
+// runOutput runs command against tasks file and returns
+// its exit code together with printed output.
+func runOutput(t *testing.T, file, name string, args ...string) (int, string) {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	code := runCommand(name, args, newStore(file, nil))
+	os.Stdout = stdout
+
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read %s output: %v", name, err)
+	}
+
+	return code, string(output)
+}
+
+// Commands print to stdout so tests capturing it can not run in parallel.
+
+func TestRunAdd(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tasks.json")
+
+	code, output := runOutput(t, file, "add", "drink", "water", "-target", "8 glasses", "-labels", "health: morning",
+		"-json")
+	if code != 0 {
+		t.Fatalf("Add should succeed got %d: %s", code, output)
+	}
+
+	added := taskSummary{}
+	if err := json.Unmarshal([]byte(output), &added); err != nil {
+		t.Fatalf("Add should print task as JSON got %q: %v", output, err)
+	}
+
+	if added.Name != "drink water" || added.Category != "health" || !slices.Equal(added.Tags, []string{"morning"}) ||
+		added.ID == "" || added.DoneToday {
+		t.Fatalf("Added task summary %+v differs from flags", added)
+	}
+
+	tasks, err := loadTasksFile(file)
+	if err != nil || len(tasks) != 1 || tasks[0].ID != added.ID || tasks[0].Target != 8 || tasks[0].Unit != "glasses" {
+		t.Fatalf("Added task should be saved got %v, %v", tasks, err)
+	}
+
+	if code, output := runOutput(t, file, "add", "-quit", "-target", "3", "smoke"); code != 1 ||
+		!strings.HasPrefix(output, "add:") {
+		t.Fatalf("Add of quit task with target should fail got %d: %s", code, output)
+	}
+
+	if code, _ := runOutput(t, file, "add", "-json"); code != 1 {
+		t.Fatalf("Add without name should fail got %d", code)
+	}
+}
+
+func TestRunDone(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tasks.json")
+	if code, output := runOutput(t, file, "add", "run"); code != 0 {
+		t.Fatalf("Add should succeed got %d: %s", code, output)
+	}
+
+	now := time.Now().In(habit.Location())
+	yesterday := now.AddDate(0, 0, -1).Format(time.DateOnly)
+	tomorrow := now.AddDate(0, 0, 1).Format(time.DateOnly)
+
+	code, output := runOutput(t, file, "done", "-json", "run")
+	if code != 0 {
+		t.Fatalf("Done should succeed got %d: %s", code, output)
+	}
+
+	stats := taskStatistics{}
+	if err := json.Unmarshal([]byte(output), &stats); err != nil {
+		t.Fatalf("Done should print statistics as JSON got %q: %v", output, err)
+	}
+
+	if stats.Name != "run" || !stats.DoneToday || stats.CurrentStrike != 1 || stats.Week.Completed == 0 {
+		t.Fatalf("Done statistics %+v should show today completion", stats)
+	}
+
+	if code, output := runOutput(t, file, "done", "run", "-date", yesterday); code != 0 || output != "run: strike 2\n" {
+		t.Fatalf("Done at yesterday should extend strike got %d: %s", code, output)
+	}
+
+	if code, output := runOutput(t, file, "undo", "-date", yesterday, "run"); code != 0 || output != "run: strike 1\n" {
+		t.Fatalf("Undo at yesterday should shorten strike got %d: %s", code, output)
+	}
+
+	if code, output := runOutput(t, file, "undo", "-date="+yesterday, "run"); code != 0 ||
+		!strings.HasPrefix(output, "undo: nothing to change") {
+		t.Fatalf("Undo of missing completion should change nothing got %d: %s", code, output)
+	}
+
+	for _, date := range []string{tomorrow, "2024-13-01", "yesterday"} {
+		if code, _ := runOutput(t, file, "done", "-date", date, "run"); code != 1 {
+			t.Fatalf("Done at %s should fail got %d", date, code)
+		}
+	}
+
+	if code, _ := runOutput(t, file, "done", "swim"); code != 1 {
+		t.Fatalf("Done of unknown habit should fail got %d", code)
+	}
+
+	tasks, err := loadTasksFile(file)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+
+	day, _ := time.ParseInLocation(time.DateOnly, yesterday, habit.Location())
+	if !tasks[0].WasCompletedToday() || tasks[0].WasCompletedAt(day.Date()) {
+		t.Fatal("Only today completion should be saved")
+	}
+}
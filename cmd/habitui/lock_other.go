@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+// lockFile does nothing on systems without flock, changes made by other
+// habitui are still detected and merged when tasks are saved.
+func lockFile(string) (func(), error) {
+	return func() {}, nil
+}
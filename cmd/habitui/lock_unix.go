@@ -0,0 +1,29 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockFile takes exclusive advisory lock on lock file kept next to filename
+// waiting until other habitui releases it. Returned function releases lock.
+func lockFile(filename string) (func(), error) {
+	file, err := os.OpenFile(filename+".lock", os.O_CREATE|os.O_RDWR, 0o600) //nolint:gomnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file of %s: %w", filename, err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("failed to lock %s: %w", filename, err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/bazko1/habitui/client"
 	"github.com/bazko1/habitui/habit"
@@ -45,6 +46,26 @@ func getIOFiles(tasksFile string) (string, string) {
 	return "", homef
 }
 
+// configureDays sets time zone and day start hour used to count habit days.
+func configureDays(timezone string, dayStart int) error {
+	loc := time.Local
+
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("failed to load time zone %q: %w", timezone, err)
+		}
+	}
+
+	habit.SetLocation(loc)
+
+	if err := habit.SetDayStartHour(dayStart); err != nil {
+		return fmt.Errorf("failed to set day start: %w", err)
+	}
+
+	return nil
+}
+
 func main() {
 	tasksFile := flag.String("data", "", "file name for loading/saving tasks data")
 	disableDebug := flag.Bool("no-debug", false, "do not log debug data to file")
@@ -54,29 +75,47 @@ func main() {
 	remoteUser := flag.String("remote-user", "", "username for remote login")
 	remotePassword := flag.String("remote-password", "", "password for remote login")
 	enableRemote := flag.Bool("enable-remote", false, "enable storing data into remote location")
+	timezone := flag.String("timezone", "", "time zone name e.g. Europe/Warsaw in which habit days are counted (default local)")
+	dayStart := flag.Int("day-start", 0, "hour at which new day begins so that completions before it count for previous day")
+	autosave := flag.Bool("autosave", true, "save tasks shortly after each change and when day changes, otherwise only on exit")
+	backups := flag.Int("backups", habit.BackupCount(), "number of previous versions of tasks file kept as backups")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s [flags] [command]:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), `Commands:
+  doctor [-fix]
+        check that statistics stored in tasks file agree with completion history
+  notes [-mood N] [query]
+        print completion notes containing query
+  list [-all] [-json]
+        print habits with today completion and current strike
+  done [-date YYYY-MM-DD] [-json] <habit>
+        mark habit completed today or at given date
+  undo [-date YYYY-MM-DD] [-json] <habit>
+        remove habit completion of today or given date
+  add [-description D] [-schedule S] [-target T] [-labels L] [-quit] [-json] <name>
+        add new habit
+  rm <habit>
+        delete habit with its whole history
+  stats [-json] <habit>
+        print habit completion and strike statistics
+  status [-format TEMPLATE] [-cache DURATION]
+        print today summary for tmux status bar or shell prompt
+  restore [N]
+        list backups of tasks file or restore N-th newest one
+Habit is given by id, name or unique name prefix. Commands use the same
+tasks file or remote server as the TUI.`)
+	}
 	flag.Parse()
 
-	var tasks habit.TaskList
-
-	var outputFile string
-
-	if !*enableRemote {
-		var inputFile string
-		inputFile, outputFile = getIOFiles(*tasksFile)
-		file, err := os.ReadFile(inputFile)
-
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			fmt.Printf("failed to open tasks file '%s': %v\n", inputFile, err)
-			os.Exit(1)
-		}
+	if err := configureDays(*timezone, *dayStart); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
-		if !errors.Is(err, os.ErrNotExist) {
-			tasks, err = habit.JSONLoadTasks(file)
-			if err != nil {
-				fmt.Println("failed to load tasks:", err)
-				os.Exit(1)
-			}
-		}
+	if err := habit.SetBackupCount(*backups); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
 	var remoteClient *client.HTTPClient
@@ -92,14 +131,23 @@ func main() {
 			Username: *remoteUser,
 			Password: *remotePassword,
 		}
+	}
 
-		var err error
+	tasksStore := newStore(*tasksFile, remoteClient)
 
-		tasks, err = remoteClient.LoadTasksOrCreateUser()
-		if err != nil {
-			fmt.Println("Failed to connect to remote is address, username and password correct?\nError:", err)
-			os.Exit(1)
-		}
+	if command := flag.Arg(0); command != "" {
+		os.Exit(runCommand(command, flag.Args()[1:], tasksStore))
+	}
+
+	tasks, err := tasksStore.load()
+	if err != nil && remoteClient != nil {
+		fmt.Println("Failed to connect to remote is address, username and password correct?\nError:", err)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
 	if !*disableDebug {
@@ -116,7 +164,8 @@ func main() {
 	logger := log.Default()
 	logger.Println("starting tui program")
 
-	model := tui.NewTuiModel(tasks)
+	model := tui.NewTuiModel(tasks).WithStorage(tasksStore, *autosave)
+
 	prog := tea.NewProgram(model)
 
 	out, err := prog.Run()
@@ -127,20 +176,37 @@ func main() {
 	model, _ = out.(tui.Model)
 
 	defer func() {
-		if remoteClient != nil {
-			if err := remoteClient.SaveUserTasks(model.Tasks()); err != nil {
-				logger.Printf("failed to save tasks to remote: %v", err)
-				os.Exit(1)
-			}
-		} else {
-			err := habit.JSONSaveTasks(outputFile, model.Tasks())
-			if err != nil {
-				logger.Printf("failed to save tasks: %v", err)
-				os.Exit(1)
-			}
+		if err := model.Conflict(); err != nil {
+			saveConflict(tasksStore, model.Tasks(), err)
+			os.Exit(1)
+		}
+
+		if _, err := tasksStore.Save(model.Tasks()); err != nil {
+			logger.Printf("failed to save tasks: %v", err)
+			saveConflict(tasksStore, model.Tasks(), err)
+			os.Exit(1)
 		}
 
 		logger.Println("saved state closing")
 		os.Exit(0)
 	}()
 }
+
+// saveConflict prints why tasks were not saved and on conflict keeps them
+// in separate file so changes made in TUI are not lost.
+func saveConflict(tasksStore *store, tasks habit.TaskList, err error) {
+	fmt.Println("failed to save tasks:", err)
+
+	if !errors.Is(err, habit.ErrConflict) {
+		return
+	}
+
+	conflictFile := tasksStore.outputFile + ".conflict"
+	if err := habit.JSONSaveTasks(conflictFile, tasks); err != nil {
+		fmt.Println("failed to save tasks for later merge:", err)
+
+		return
+	}
+
+	fmt.Printf("tasks were changed by other habitui, your tasks are saved in %s\n", conflictFile)
+}
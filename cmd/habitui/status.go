@@ -0,0 +1,99 @@
+//nolint:forbidigo //prints for command line client are not debug statements
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+const (
+	defaultStatusFormat = "{{.Done}}/{{.Total}} habits done, 🔥{{.Strike}}"
+	defaultStatusCache  = time.Minute
+)
+
+// runStatus prints short summary of today habits for tmux or shell prompt.
+func runStatus(args []string, tasksStore *store) int {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	format := flags.String("format", defaultStatusFormat,
+		"Go template with fields .Done .Pending .Total .Strike .PendingNames and join function")
+	cacheTTL := flags.Duration("cache", defaultStatusCache, "how long tasks loaded from remote server are reused")
+	_ = parseArgs(flags, args)
+
+	tmpl, err := template.New("status").Funcs(template.FuncMap{"join": strings.Join}).Parse(*format)
+	if err != nil {
+		fmt.Println("status: invalid format:", err)
+
+		return 1
+	}
+
+	tasks, err := loadStatusTasks(tasksStore, *cacheTTL)
+	if err != nil {
+		fmt.Println(err)
+
+		return 1
+	}
+
+	if err := tmpl.Execute(os.Stdout, tasks.TodayStatus()); err != nil {
+		fmt.Println("status: failed to format status:", err)
+
+		return 1
+	}
+
+	fmt.Println()
+
+	return 0
+}
+
+// loadStatusTasks loads tasks from store. Tasks of remote server are
+// kept in cache file and loaded again only after ttl passes. Stale
+// cache is used when remote server can not be reached.
+func loadStatusTasks(tasksStore *store, ttl time.Duration) (habit.TaskList, error) {
+	if tasksStore.remote == nil {
+		return tasksStore.load()
+	}
+
+	cacheFile := statusCacheFile(tasksStore)
+	if info, err := os.Stat(cacheFile); err == nil && time.Since(info.ModTime()) < ttl {
+		if tasks, err := loadTasksFile(cacheFile); err == nil {
+			return tasks, nil
+		}
+	}
+
+	tasks, err := tasksStore.load()
+	if err != nil {
+		if cached, cacheErr := loadTasksFile(cacheFile); cacheErr == nil {
+			return cached, nil
+		}
+
+		return nil, err
+	}
+
+	// cache is written without backups of tasks file
+	if data, err := json.Marshal(tasks); err == nil && os.MkdirAll(filepath.Dir(cacheFile), 0o700) == nil { //nolint:gomnd
+		_ = os.WriteFile(cacheFile, data, 0o600) //nolint:gomnd
+	}
+
+	return tasks, nil
+}
+
+// statusCacheFile returns path of cache file for remote server and user of store.
+func statusCacheFile(tasksStore *store) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	sum := sha256.Sum256([]byte(tasksStore.remote.Address + "\x00" + tasksStore.remote.Username))
+
+	return filepath.Join(dir, "habitui", "status-"+hex.EncodeToString(sum[:8])+".json")
+}
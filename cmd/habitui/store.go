@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bazko1/habitui/client"
+	"github.com/bazko1/habitui/habit"
+)
+
+// store loads and saves tasks from local file or remote server
+// so that TUI and commands share the same data. Tasks file is locked
+// while tasks are merged and written so changes made to it by other
+// habitui since tasks were loaded are merged instead of being overwritten.
+// File is replaced atomically so it is read without lock.
+// Store is safe to use from TUI saving tasks in background.
+type store struct {
+	mu         sync.Mutex
+	inputFile  string
+	outputFile string
+	remote     *client.HTTPClient
+	// base is tasks as they were last loaded or saved, it is common
+	// ancestor of our and other habitui changes when they are merged.
+	base    habit.TaskList
+	modTime time.Time
+	size    int64
+	// backedUp is set once save changed tasks file and kept its previous
+	// content as backup, later saves e.g. TUI autosaves do not rotate
+	// backups so that they are not all taken by recent versions.
+	backedUp bool
+}
+
+// newStore returns store using remote server if it is given
+// and tasks file found by getIOFiles otherwise.
+func newStore(tasksFile string, remote *client.HTTPClient) *store {
+	inputFile, outputFile := getIOFiles(tasksFile)
+
+	return &store{inputFile: inputFile, outputFile: outputFile, remote: remote, base: habit.TaskList{}}
+}
+
+// load returns stored tasks. Missing tasks file means there are no tasks yet.
+func (s *store) load() (habit.TaskList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.remote != nil {
+		tasks, err := s.remote.LoadTasksOrCreateUser()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		s.base = tasks.Clone()
+
+		return tasks, nil
+	}
+
+	// file replaced after stat is seen as changed by the next Reload
+	modTime, size := fileStat(s.inputFile)
+
+	tasks, err := readTasksFile(s.inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s.base = tasks.Clone()
+	s.modTime, s.size = modTime, size
+
+	return tasks, nil
+}
+
+// Save stores tasks merged with changes saved by other habitui since
+// tasks were loaded and returns merged tasks. Nothing is saved and
+// error wrapping habit.ErrConflict is returned when the same task was
+// changed differently by both.
+func (s *store) Save(tasks habit.TaskList) (habit.TaskList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.remote != nil {
+		current, err := s.remote.LoadTasksOrCreateUser()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		merged, err := s.merge(tasks, current)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.saveRemote(current, merged); err != nil {
+			return nil, err
+		}
+
+		s.base = merged.Clone()
+
+		return merged, nil
+	}
+
+	unlock, err := lockFile(s.outputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	current, err := readTasksFile(s.outputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := s.merge(tasks, current)
+	if err != nil {
+		return nil, err
+	}
+
+	save := habit.JSONSaveTasks
+	if s.backedUp {
+		save = habit.JSONReplaceTasks
+	}
+
+	if err := save(s.outputFile, merged); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	s.backedUp = s.backedUp || !sameTasks(current, merged)
+
+	s.base = merged.Clone()
+	s.modTime, s.size = fileStat(s.outputFile)
+
+	return merged, nil
+}
+
+// Reload checks whether tasks file was changed by other habitui and if so
+// returns tasks merged with the changes. On conflict tasks are merged
+// keeping ours and error wrapping habit.ErrConflict is returned so that
+// they are not saved over other changes later. Remote tasks are not reloaded.
+func (s *store) Reload(tasks habit.TaskList) (habit.TaskList, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.remote != nil {
+		return tasks, false, nil
+	}
+
+	modTime, size := fileStat(s.outputFile)
+	if modTime.Equal(s.modTime) && size == s.size {
+		return tasks, false, nil
+	}
+
+	current, err := readTasksFile(s.outputFile)
+	if err != nil {
+		return tasks, false, err
+	}
+
+	s.modTime, s.size = modTime, size
+
+	merged, conflicts := habit.MergeTasks(s.base, tasks, current)
+	if len(conflicts) > 0 {
+		return merged, true, conflictError(conflicts)
+	}
+
+	s.base = current
+
+	return merged, true, nil
+}
+
+// saveRemote sends to remote server only habits that differ from current
+// remote ones and deletes habits removed from tasks so that habits changed
+// by other client in the meantime are not overwritten.
+func (s *store) saveRemote(current, tasks habit.TaskList) error {
+	for _, task := range tasks {
+		if idx := current.IndexByID(task.ID); idx >= 0 &&
+			sameTasks(current[idx:idx+1], habit.TaskList{task}) {
+			continue
+		}
+
+		if err := s.remote.SaveUserTask(task); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	for _, task := range current {
+		if tasks.IndexByID(task.ID) >= 0 {
+			continue
+		}
+
+		if err := s.remote.DeleteUserTask(task.ID); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// merge merges tasks with current ones using base as common ancestor.
+func (s *store) merge(tasks, current habit.TaskList) (habit.TaskList, error) {
+	merged, conflicts := habit.MergeTasks(s.base, tasks, current)
+	if len(conflicts) > 0 {
+		return nil, conflictError(conflicts)
+	}
+
+	return merged, nil
+}
+
+func conflictError(conflicts []string) error {
+	return fmt.Errorf("%w in %s", habit.ErrConflict, strings.Join(conflicts, ", "))
+}
+
+// sameTasks returns whether both lists would be saved the same way.
+func sameTasks(one, other habit.TaskList) bool {
+	oneJSON, _ := json.Marshal(one)
+	otherJSON, _ := json.Marshal(other)
+
+	return bytes.Equal(oneJSON, otherJSON)
+}
+
+// readTasksFile loads tasks file treating missing file as empty tasks list.
+func readTasksFile(filename string) (habit.TaskList, error) {
+	tasks, err := loadTasksFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return habit.TaskList{}, nil
+	}
+
+	return tasks, err
+}
+
+// fileStat returns modification time and size of file used to detect that
+// it was changed, zero values are returned for missing file.
+func fileStat(filename string) (time.Time, int64) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}, 0
+	}
+
+	return info.ModTime(), info.Size()
+}
+
+// loadTasksFile loads tasks saved in JSON file.
+func loadTasksFile(filename string) (habit.TaskList, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tasks file '%s': %w", filename, err)
+	}
+
+	tasks, err := habit.JSONLoadTasks(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazko1/habitui/client"
+	"github.com/bazko1/habitui/habit"
+	"github.com/bazko1/habitui/server"
+)
+
+func TestStoreMergesOtherWriter(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "tasks.json")
+	ours, other := newStore(file, nil), newStore(file, nil)
+
+	tasks, err := ours.load()
+	if err != nil {
+		t.Fatalf("Failed to load missing tasks file: %v", err)
+	}
+
+	if _, err := other.load(); err != nil {
+		t.Fatalf("Failed to load missing tasks file: %v", err)
+	}
+
+	if _, err := other.Save(habit.TaskList{habit.NewTask("run", "")}); err != nil {
+		t.Fatalf("Failed to save other tasks: %v", err)
+	}
+
+	tasks, changed, err := ours.Reload(append(tasks, habit.NewTask("read", "")))
+	if err != nil || !changed || len(tasks) != 2 {
+		t.Fatalf("Reload should merge task of other writer got %d tasks, %v, %v", len(tasks), changed, err)
+	}
+
+	if _, err := ours.Save(tasks); err != nil {
+		t.Fatalf("Failed to save merged tasks: %v", err)
+	}
+
+	// our own save is not a change made by other writer
+	if _, changed, err := ours.Reload(tasks); changed || err != nil {
+		t.Fatalf("Own save should not be reloaded got %v, %v", changed, err)
+	}
+
+	otherTasks, changed, err := other.Reload(habit.TaskList{})
+	if err != nil || !changed {
+		t.Fatalf("Other writer should reload saved tasks got %v, %v", changed, err)
+	}
+
+	// other writer removed run after it was saved so it stays removed
+	if len(otherTasks) != 1 || otherTasks[0].Name != "read" {
+		t.Fatalf("Other writer should have only read task got %v", otherTasks)
+	}
+}
+
+func TestStoreConflict(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "tasks.json")
+	if _, err := newStore(file, nil).Save(habit.TaskList{habit.NewTask("run", "")}); err != nil {
+		t.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	ours, other := newStore(file, nil), newStore(file, nil)
+	tasks, _ := ours.load()
+	otherTasks, _ := other.load()
+
+	otherTasks[0].Description = "5 km"
+	if _, err := other.Save(otherTasks); err != nil {
+		t.Fatalf("Failed to save other tasks: %v", err)
+	}
+
+	tasks[0].Description = "10 km"
+	if _, err := ours.Save(tasks); !errors.Is(err, habit.ErrConflict) {
+		t.Fatalf("Saving differently changed task should conflict got %v", err)
+	}
+
+	if _, _, err := ours.Reload(tasks); !errors.Is(err, habit.ErrConflict) {
+		t.Fatalf("Reloading differently changed task should conflict got %v", err)
+	}
+
+	stored, err := loadTasksFile(file)
+	if err != nil || stored[0].Description != "5 km" {
+		t.Fatalf("Conflicting save should keep other tasks got %v, %v", stored, err)
+	}
+}
+
+func TestStoreBackupOncePerSession(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "tasks.json")
+	if _, err := newStore(file, nil).Save(habit.TaskList{habit.NewTask("run", "")}); err != nil {
+		t.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	session := newStore(file, nil)
+	tasks, _ := session.load()
+
+	for range 3 {
+		tasks[0].MakeCompleted()
+		tasks[0].Description += "."
+
+		if _, err := session.Save(tasks); err != nil {
+			t.Fatalf("Failed to save tasks: %v", err)
+		}
+	}
+
+	if backups, _ := habit.Backups(file); len(backups) != 1 {
+		t.Fatalf("Only the first save of session should keep backup got %d backups", len(backups))
+	}
+
+	next := newStore(file, nil)
+	_, _ = next.load()
+
+	if _, err := next.Save(habit.TaskList{}); err != nil {
+		t.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	if backups, _ := habit.Backups(file); len(backups) != 2 {
+		t.Fatalf("Save of new session should keep backup got %d backups", len(backups))
+	}
+}
+
+func TestStoreRemoteSavesChangedHabits(t *testing.T) {
+	t.Parallel()
+
+	srv, _, err := server.New(server.WithControllerEngine("inmem"))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	httpServer := httptest.NewServer(srv.Handler)
+	defer httpServer.Close()
+
+	remote := &client.HTTPClient{Address: httpServer.URL, Username: "foo", Password: "test"}
+	ours, other := newStore("", remote), newStore("", remote)
+
+	tasks, err := ours.load()
+	if err != nil {
+		t.Fatalf("Failed to load remote tasks: %v", err)
+	}
+
+	if tasks, err = ours.Save(append(tasks, habit.NewTask("run", ""))); err != nil {
+		t.Fatalf("Failed to save remote tasks: %v", err)
+	}
+
+	otherTasks, err := other.load()
+	if err != nil || len(otherTasks) != 1 {
+		t.Fatalf("Other client should load saved task got %v, %v", otherTasks, err)
+	}
+
+	if _, err := ours.Save(append(tasks, habit.NewTask("read", ""))); err != nil {
+		t.Fatalf("Failed to save remote tasks: %v", err)
+	}
+
+	// other client renames run without knowing about read
+	otherTasks[0].Name = "run 5k"
+	if otherTasks, err = other.Save(otherTasks); err != nil || len(otherTasks) != 2 {
+		t.Fatalf("Other client should merge read task got %v, %v", otherTasks, err)
+	}
+
+	if _, err := other.Save(otherTasks[:1]); err != nil {
+		t.Fatalf("Failed to save remote tasks: %v", err)
+	}
+
+	stored, err := remote.LoadTasksOrCreateUser()
+	if err != nil || len(stored) != 1 || stored[0].Name != "run 5k" {
+		t.Fatalf("Remote should have only renamed run task got %v, %v", stored, err)
+	}
+}
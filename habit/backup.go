@@ -0,0 +1,188 @@
+package habit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBackupCount = 5
+	// backupTimeLayout is layout of time in backup file name that sorts
+	// the same way as times it represents.
+	backupTimeLayout = "20060102-150405.000000000"
+	backupSuffix     = ".bak"
+)
+
+var ErrInvalidBackupCount = errors.New("invalid backup count")
+
+// backupCount is number of the latest backups kept when tasks are saved.
+var backupCount = defaultBackupCount //nolint:gochecknoglobals
+
+// SetBackupCount sets how many previous versions of tasks file are kept
+// as backups by JSONSaveTasks. Zero disables backups.
+func SetBackupCount(count int) error {
+	if count < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidBackupCount, count)
+	}
+
+	backupCount = count
+
+	return nil
+}
+
+// BackupCount returns how many backups of tasks file are kept.
+func BackupCount() int {
+	return backupCount
+}
+
+// Backup is previous version of tasks file.
+type Backup struct {
+	Path string
+	Time time.Time
+}
+
+// Backups returns backups of tasks file ordered from the newest.
+func Backups(filename string) ([]Backup, error) {
+	prefix := filepath.Base(filename) + "."
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups of %s: %w", filename, err)
+	}
+
+	backups := []Backup{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+
+		created, err := time.ParseInLocation(backupTimeLayout,
+			strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupSuffix), time.Local)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{Path: filepath.Join(filepath.Dir(filename), name), Time: created})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int { return b.Time.Compare(a.Time) })
+
+	return backups, nil
+}
+
+// RestoreBackup replaces tasks file with backup after checking that backup
+// can be loaded. Replaced file becomes a backup so restore can be reverted.
+func RestoreBackup(filename string, backup Backup) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup %s: %w", backup.Path, err)
+	}
+
+	if _, err := JSONLoadTasks(data); err != nil {
+		return fmt.Errorf("backup %s is not valid tasks file: %w", backup.Path, err)
+	}
+
+	return saveWithBackup(filename, data)
+}
+
+// saveWithBackup keeps current content of file as backup unless it is
+// empty or the same as data and then atomically replaces file with data.
+func saveWithBackup(filename string, data []byte) error {
+	if backupCount > 0 {
+		previous, err := os.ReadFile(filename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read %s for backup: %w", filename, err)
+		}
+
+		if len(previous) > 0 && !bytes.Equal(previous, data) {
+			backup := fmt.Sprintf("%s.%s%s", filename, time.Now().Format(backupTimeLayout), backupSuffix)
+			if err := writeFileAtomic(backup, previous); err != nil {
+				return fmt.Errorf("failed to backup %s: %w", filename, err)
+			}
+
+			if err := pruneBackups(filename); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeFileAtomic(filename, data)
+}
+
+// pruneBackups removes backups of file over backupCount starting with the oldest.
+func pruneBackups(filename string) error {
+	backups, err := Backups(filename)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(backupCount, len(backups)):] {
+		if err := os.Remove(backup.Path); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to temporary file in the same directory,
+// syncs it to disk and renames it over filename so that the file has
+// either previous or new content even if writing is interrupted.
+func writeFileAtomic(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", filename, err)
+	}
+
+	defer os.Remove(file.Name())
+
+	mode := os.FileMode(0o644) //nolint:gomnd
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to set mode of %s: %w", filename, err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to sync %s: %w", filename, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", filename, err)
+	}
+
+	if err := os.Rename(file.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filename, err)
+	}
+
+	// rename itself is persisted once directory is synced, not every
+	// system supports it so error is ignored
+	if dirFile, err := os.Open(dir); err == nil {
+		_ = dirFile.Sync()
+		dirFile.Close()
+	}
+
+	return nil
+}
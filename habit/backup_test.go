@@ -0,0 +1,83 @@
+package habit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestSaveBackups(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "tasks.json")
+	saves := habit.BackupCount() + 2
+
+	for version := range saves {
+		tasks := habit.TaskList{habit.NewTask("version "+strconv.Itoa(version), "")}
+		if err := habit.JSONSaveTasks(filename, tasks); err != nil {
+			t.Fatalf("Failed to save tasks: %v", err)
+		}
+	}
+
+	// saving the same tasks again does not rotate backups
+	current, _ := os.ReadFile(filename)
+	tasks, _ := habit.JSONLoadTasks(current)
+
+	if err := habit.JSONSaveTasks(filename, tasks); err != nil {
+		t.Fatalf("Failed to save tasks: %v", err)
+	}
+
+	backups, err := habit.Backups(filename)
+	if err != nil {
+		t.Fatalf("Failed to list backups: %v", err)
+	}
+
+	if len(backups) != habit.BackupCount() {
+		t.Fatalf("Expected %d backups got %d", habit.BackupCount(), len(backups))
+	}
+
+	if entries, _ := os.ReadDir(filepath.Dir(filename)); len(entries) != len(backups)+1 {
+		t.Fatalf("Expected only tasks file and backups in directory got %d files", len(entries))
+	}
+
+	if err := habit.RestoreBackup(filename, backups[0]); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	restored, _ := os.ReadFile(filename)
+	if previous := "version " + strconv.Itoa(saves-2); !strings.Contains(string(restored), previous) {
+		t.Fatalf("Restored file should contain %q got %s", previous, restored)
+	}
+
+	if backups, _ = habit.Backups(filename); len(backups) != habit.BackupCount() {
+		t.Fatalf("Expected %d backups after restore got %d", habit.BackupCount(), len(backups))
+	}
+
+	if newest, _ := os.ReadFile(backups[0].Path); !strings.Contains(string(newest), "version "+strconv.Itoa(saves-1)) {
+		t.Fatalf("Restored file should be kept as the newest backup got %s", newest)
+	}
+}
+
+func TestRestoreInvalidBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "tasks.json")
+	backup := habit.Backup{Path: filepath.Join(dir, "tasks.json.bak")}
+
+	if err := os.WriteFile(backup.Path, []byte("{truncated"), 0o600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+
+	if err := habit.RestoreBackup(filename, backup); err == nil {
+		t.Fatal("Restoring invalid backup should fail")
+	}
+
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatal("Tasks file should not be created from invalid backup")
+	}
+}
@@ -0,0 +1,106 @@
+package habit
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// StatsIssue describes derived task statistic which value stored
+// in data file differs from the value computed from completion history.
+type StatsIssue struct {
+	Task   string
+	Field  string
+	Stored string
+	Actual string
+}
+
+func (issue StatsIssue) String() string {
+	return fmt.Sprintf("task %q: %s is %s while history gives %s", issue.Task, issue.Field, issue.Stored, issue.Actual)
+}
+
+// JSONCheckTasks loads tasks from json bytes and reports every stored statistic
+// that disagrees with completion history. Older versions are compared after
+// upgrading them to the latest version. Returned tasks have statistics
+// recomputed so saving them repairs the data.
+func JSONCheckTasks(bytes []byte) (TaskList, []StatsIssue, error) {
+	migrated, err := MigrateJSON(bytes, TaskVersionLatest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("check json failed to migrate tasks: %w", err)
+	}
+
+	stored := []taskJSON{}
+	if err := json.Unmarshal(migrated, &stored); err != nil {
+		return nil, nil, fmt.Errorf("check json failed to umarshal bytes: %w", err)
+	}
+
+	tasks, err := JSONLoadTasks(bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// loaded tasks are sorted by Position so they are matched by ID
+	issues := []StatsIssue{}
+
+	for _, task := range tasks {
+		if idx := slices.IndexFunc(stored, func(s taskJSON) bool { return s.ID == task.ID }); idx >= 0 {
+			issues = append(issues, stored[idx].statsIssues(task)...)
+		}
+	}
+
+	return tasks, issues, nil
+}
+
+// statsIssues compares statistics stored in json with ones recomputed for task.
+func (stored taskJSON) statsIssues(task Task) []StatsIssue {
+	issues := []StatsIssue{}
+	report := func(field, storedVal, actualVal string) {
+		issues = append(issues, StatsIssue{Task: task.Name, Field: field, Stored: storedVal, Actual: actualVal})
+	}
+
+	if actual := formatDates(task.completions()); !slices.Equal(stored.History.Completions, actual) {
+		report("History.Completions",
+			strconv.Itoa(len(stored.History.Completions))+" completions",
+			strconv.Itoa(len(actual))+" sorted unique completions")
+	}
+
+	if actual := formatDate(task.lastTimeCompleted); stored.Stats.LastCompleted != actual {
+		report("Stats.LastCompleted", formatCheckDate(stored.Stats.LastCompleted), formatCheckDate(actual))
+	}
+
+	if stored.Stats.CurrentStrike != task.currentStrike {
+		report("Stats.CurrentStrike", strconv.Itoa(stored.Stats.CurrentStrike), strconv.Itoa(task.currentStrike))
+	}
+
+	actualBest := monthlyBestJSON(task.yearlyBestStrike)
+	for _, month := range slices.Sorted(joinKeys(stored.Stats.MonthlyBestStrike, actualBest)) {
+		if s, a := stored.Stats.MonthlyBestStrike[month], actualBest[month]; s != a {
+			report(fmt.Sprintf("Stats.MonthlyBestStrike[%s]", month), strconv.Itoa(s), strconv.Itoa(a))
+		}
+	}
+
+	return issues
+}
+
+// joinKeys returns keys present in any of given maps.
+func joinKeys[M ~map[K]V, K comparable, V any](one, other M) iter.Seq[K] {
+	keys := maps.Clone(one)
+	if keys == nil {
+		keys = M{}
+	}
+
+	maps.Copy(keys, other)
+
+	return maps.Keys(keys)
+}
+
+func formatCheckDate(date string) string {
+	if date == "" {
+		return "never"
+	}
+
+	return date
+}
@@ -0,0 +1,85 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidDayStartHour = errors.New("invalid day start hour")
+
+// Civil day configuration shared by all tasks. When location is nil
+// dates are taken in the location that each time value carries.
+var (
+	location     *time.Location //nolint:gochecknoglobals
+	dayStartHour int            //nolint:gochecknoglobals
+)
+
+// SetLocation sets time zone in which task dates are computed.
+// Completions stored with other offsets, e.g. before daylight saving
+// change or travel, are counted for their day in this location.
+// Passing nil restores using location of each time value.
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// Location returns configured time zone or time.Local if none was set.
+func Location() *time.Location {
+	if location == nil {
+		return time.Local
+	}
+
+	return location
+}
+
+// SetDayStartHour sets hour at which new day begins so that completions
+// done after midnight but before that hour count for the previous day.
+func SetDayStartHour(hour int) error {
+	//// nolint:gomnd // hours of day
+	if hour < 0 || hour > 23 {
+		return fmt.Errorf("%w: %d is not in range 0-23", ErrInvalidDayStartHour, hour)
+	}
+
+	dayStartHour = hour
+
+	return nil
+}
+
+// DayStartHour returns hour at which new day begins.
+func DayStartHour() int {
+	return dayStartHour
+}
+
+// inLocation returns date in configured location.
+func inLocation(date time.Time) time.Time {
+	if location == nil {
+		return date
+	}
+
+	return date.In(location)
+}
+
+// civilTime returns moment t in configured location. Moments before
+// day start hour are moved to the last moment of the previous day.
+func civilTime(t time.Time) time.Time {
+	t = inLocation(t)
+	if t.Hour() >= dayStartHour {
+		return t
+	}
+
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// Now returns current moment as seen in configured location with day
+// start hour applied.
+func Now() time.Time {
+	return civilTime(time.Now())
+}
+
+// Now returns current GetTime moment as seen in configured location
+// with day start hour applied. All day based statistics use this time.
+func (task *Task) Now() time.Time {
+	return civilTime(task.GetTime())
+}
@@ -0,0 +1,105 @@
+package habit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+//nolint:paralleltest // changes civil day configuration shared by all tasks
+func TestCivilDates(t *testing.T) {
+	habit.SetLocation(time.FixedZone("UTC+9", 9*60*60))
+
+	t.Cleanup(func() {
+		habit.SetLocation(nil)
+		_ = habit.SetDayStartHour(0)
+	})
+
+	// 2024-05-02 05:00 in UTC+9
+	now := time.Date(2024, time.May, 1, 20, 0, 0, 0, time.UTC)
+	task := habit.WithCustomTime("journal", "", func() time.Time { return now })
+	task.MakeCompleted()
+	// 2024-05-01 09:00 in UTC+9
+	task.MakeCompletedAt(time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	if !task.WasCompletedAt(2024, time.May, 2) || !task.WasCompletedAt(2024, time.May, 1) {
+		t.Fatal("Task should be completed on 1st and 2nd of May in configured location")
+	}
+
+	if err := validateStrike(task, 2, 2, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := habit.SetDayStartHour(24); !errors.Is(err, habit.ErrInvalidDayStartHour) {
+		t.Fatalf("Setting day start hour to 24 should fail with invalid hour error got %v", err)
+	}
+
+	if err := habit.SetDayStartHour(4); err != nil {
+		t.Fatalf("Failed to set day start hour: %v", err)
+	}
+
+	// 2024-05-03 03:30 in UTC+9 still counts for 2nd of May
+	now = time.Date(2024, time.May, 2, 18, 30, 0, 0, time.UTC)
+	late := habit.WithCustomTime("read", "", func() time.Time { return now })
+	late.MakeCompleted()
+
+	if !late.WasCompletedAt(2024, time.May, 2) || !late.WasCompletedToday() {
+		t.Fatal("Task completed before day start hour should count for previous day")
+	}
+
+	habit.SetLocation(time.FixedZone("UTC+1", 60*60))
+
+	// completion stored during summer time belongs to March in UTC+1
+	tasks, err := habit.JSONLoadTasks([]byte(`[{"Version":"v1","Name":"walk","Description":"",
+		"CreationDate":"2024-03-01T12:00:00+01:00",
+		"YearlyTaskCompletion":{"2024":{"3":["2024-03-30T20:00:00+01:00"],"4":["2024-04-01T00:30:00+02:00"]}}}]`))
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	if !tasks[0].WasCompletedAt(2024, time.March, 31) || tasks[0].MonthCompletion(2024, time.April) != 0 {
+		t.Fatal("Loaded completion should be moved to 31st of March in configured location")
+	}
+}
+
+//nolint:paralleltest // changes civil day configuration shared by all tasks
+func TestDayStartHourWeekCompletion(t *testing.T) {
+	habit.SetLocation(time.UTC)
+
+	t.Cleanup(func() {
+		habit.SetLocation(nil)
+		_ = habit.SetDayStartHour(0)
+	})
+
+	if err := habit.SetDayStartHour(4); err != nil {
+		t.Fatalf("Failed to set day start hour: %v", err)
+	}
+
+	// created on Monday 2024-04-01
+	dit := dayIncreasingTime{time.Date(2024, time.April, 1, 20, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("gym", "", dit.Now)
+	task.Schedule = habit.TimesPerWeekSchedule(2)
+	task.MakeCompleted()
+
+	// 01:30 on Monday still counts for Sunday 7th
+	dit.CurrentTime = time.Date(2024, time.April, 8, 1, 30, 0, 0, time.UTC)
+	task.MakeCompleted()
+
+	if !task.WasCompletedToday() || task.CurrentWeekCompletion() != 2 {
+		t.Fatalf("Completion after midnight should count for the week got %d", task.CurrentWeekCompletion())
+	}
+
+	// week target was reached so strike continues on Tuesday 9th
+	dit.CurrentTime = time.Date(2024, time.April, 9, 20, 0, 0, 0, time.UTC)
+	task.MakeCompleted()
+
+	if task.CurrentStrike() != 3 {
+		t.Fatalf("Strike should be 3 when week target was reached got %d", task.CurrentStrike())
+	}
+
+	if task.WeekCompletion(2024, time.April, 7) != 2 {
+		t.Fatalf("Past week should have 2 completions got %d", task.WeekCompletion(2024, time.April, 7))
+	}
+}
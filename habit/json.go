@@ -4,23 +4,81 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 )
 
-type taskJSON struct {
-	Version      string
-	Name         string
-	Description  string
-	CreationDate time.Time
+// middayHour is hour of day at which stored civil dates are loaded.
+const middayHour = 12
 
-	YearlyTaskCompletion   YearlyTaskCompletion
-	LastTimeCompleted      time.Time
-	CurrentStrike          int
-	BestStrikeThisWeek     int
-	StrikeThisMonth        Strike
-	YearlyBestStrike       YearlyBestStrike
-	BestStrikeLastFinished time.Time
+// Latest task file format is used to load and save tasks. Once new version
+// is added aliases point to its types while types of older versions are
+// kept unchanged for migrations from them.
+type (
+	taskJSON    = taskJSONV2
+	historyJSON = historyJSONV2
+	noteJSON    = noteJSONV2
+	rangeJSON   = rangeJSONV2
+	statsJSON   = statsJSONV2
+	strikeJSON  = strikeJSONV2
+)
+
+// taskJSONV2 is task format v2. Days are stored as civil dates in
+// "YYYY-MM-DD" format so they do not depend on the offset completion
+// was done with. Stats are derived from History and are stored only
+// for readers of the file as they are recomputed on load.
+type taskJSONV2 struct {
+	Version     string
+	ID          string
+	Name        string
+	Description string
+	Category    string   `json:",omitempty"`
+	Tags        []string `json:",omitempty"`
+	Archived    bool     `json:",omitempty"`
+	Position    int      `json:",omitempty"`
+	Created     string
+	Kind        TaskKind `json:",omitempty"`
+	Unit        string   `json:",omitempty"`
+	Target      int      `json:",omitempty"`
+	Schedule    Schedule
+	History     historyJSONV2
+	Stats       statsJSONV2
+}
+
+type historyJSONV2 struct {
+	Completions []string
+	Amounts     map[string]int `json:",omitempty"`
+	Skipped     []string       `json:",omitempty"`
+	Vacations   []rangeJSONV2  `json:",omitempty"`
+	// Pauses have empty To while pause lasts.
+	Pauses []rangeJSONV2 `json:",omitempty"`
+	// Notes are keyed by "YYYY-MM-DD" day of completion.
+	Notes map[string]noteJSONV2 `json:",omitempty"`
+}
+
+type noteJSONV2 struct {
+	Text string
+	Mood int `json:",omitempty"`
+}
+
+type rangeJSONV2 struct {
+	From string
+	To   string
+}
+
+type statsJSONV2 struct {
+	LastCompleted string `json:",omitempty"`
+	CurrentStrike int
+	AllTimeStrike strikeJSONV2
+	MonthStrike   strikeJSONV2
+	WeekStrike    strikeJSONV2
+	// MonthlyBestStrike is keyed by "YYYY-MM" month.
+	MonthlyBestStrike map[string]int `json:",omitempty"`
+}
+
+type strikeJSONV2 struct {
+	Count        int
+	Best         int
+	LastFinished string `json:",omitempty"`
 }
 
 func (t *TaskList) Scan(value interface{}) error {
@@ -33,59 +91,315 @@ func (t TaskList) Value() (driver.Value, error) {
 	return string(b), err
 }
 
+// UnmarshalJSON loads list of tasks rejecting lists where
+// more than one task has the same ID.
+func (t *TaskList) UnmarshalJSON(data []byte) error {
+	raw := []json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal TaskList: %w", err)
+	}
+
+	tasks := make(TaskList, len(raw))
+	ids := make(map[string]string, len(raw))
+
+	for i := range raw {
+		if err := json.Unmarshal(raw[i], &tasks[i]); err != nil {
+			return err
+		}
+
+		if name, exists := ids[tasks[i].ID]; exists {
+			return fmt.Errorf("%w: tasks %q and %q have the same id %s", ErrDuplicateID, name, tasks[i].Name, tasks[i].ID)
+		}
+
+		ids[tasks[i].ID] = tasks[i].Name
+	}
+
+	tasks.SortByPosition()
+	*t = tasks
+
+	return nil
+}
+
 func (task Task) MarshalJSON() ([]byte, error) {
-	if task.Version == "" {
-		task.Version = TaskVersionLatest
-	}
-
-	bytes, err := json.Marshal(taskJSON{
-		Version:                task.Version,
-		Name:                   task.Name,
-		Description:            task.Description,
-		CreationDate:           task.CreationDate,
-		YearlyTaskCompletion:   task.yearlyTaskCompletion,
-		LastTimeCompleted:      task.lastTimeCompleted,
-		CurrentStrike:          task.currentStrike,
-		StrikeThisMonth:        task.strikeThisMonth,
-		YearlyBestStrike:       task.yearlyBestStrike,
-		BestStrikeLastFinished: task.bestStrikeLastFinished,
-	})
+	stored := taskJSON{
+		Version:     TaskVersionLatest,
+		ID:          task.ID,
+		Name:        task.Name,
+		Description: task.Description,
+		Category:    task.Category,
+		Tags:        task.Tags,
+		Archived:    task.Archived,
+		Position:    task.Position,
+		Created:     formatDate(task.CreationDate),
+		Kind:        task.Kind,
+		Unit:        task.Unit,
+		Target:      task.Target,
+		Schedule:    task.Schedule,
+		History: historyJSON{
+			Completions: formatDates(task.completions()),
+			Amounts:     amountsJSON(task.yearlyTaskAmount),
+			Skipped:     formatDates(task.skippedDays),
+			Vacations:   rangesJSON(task.vacations),
+			Pauses:      rangesJSON(task.pauses),
+			Notes:       notesJSON(task.notes),
+		},
+		Stats: statsJSON{
+			LastCompleted:     formatDate(task.lastTimeCompleted),
+			CurrentStrike:     task.currentStrike,
+			AllTimeStrike:     newStrikeJSON(task.strikeAllTime),
+			MonthStrike:       newStrikeJSON(task.strikeThisMonth),
+			WeekStrike:        newStrikeJSON(task.strikeThisWeek),
+			MonthlyBestStrike: monthlyBestJSON(task.yearlyBestStrike),
+		},
+	}
+
+	bytes, err := json.Marshal(stored)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal Task: %w", err)
 	}
-	// TODO: For now I stick with extra struct that capitalizes
-	// all the fields. I might want to write un/marshaler that
-	// converts struct to map[string]any and loops over field
-	// names and capitalizes them so that I do not need to maintain
-	// both taskJSON and Task.
 
 	return bytes, nil
 }
 
+// UnmarshalJSON loads task from json of any supported version
+// upgrading older versions to the latest one.
 func (task *Task) UnmarshalJSON(data []byte) error {
-	jsonTask := taskJSON{}
-	if err := json.Unmarshal(data, &jsonTask); err != nil {
+	data, err := migrateTask(data, TaskVersionLatest)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Task: %w", err)
+	}
+
+	stored := taskJSON{}
+	if err := json.Unmarshal(data, &stored); err != nil {
 		return fmt.Errorf("failed to unmarshal Task: %w", err)
 	}
 
-	task.Version = jsonTask.Version
-	task.Name = jsonTask.Name
-	task.Description = jsonTask.Description
-	task.CreationDate = jsonTask.CreationDate
-	task.GetTime = time.Now
-	task.yearlyTaskCompletion = jsonTask.YearlyTaskCompletion
-	task.lastTimeCompleted = jsonTask.LastTimeCompleted
-	task.currentStrike = jsonTask.CurrentStrike
-	task.yearlyBestStrike = jsonTask.YearlyBestStrike
-	task.bestStrikeLastFinished = jsonTask.BestStrikeLastFinished
+	if err := task.fromJSON(stored); err != nil {
+		return fmt.Errorf("failed to unmarshal Task %q: %w", stored.Name, err)
+	}
+
+	task.RecomputeStats()
+
+	return nil
+}
+
+func (task *Task) fromJSON(stored taskJSON) error {
+	if err := stored.Schedule.Validate(); err != nil {
+		return err
+	}
+
+	created, err := parseDate(stored.Created)
+	if err != nil {
+		return err
+	}
+
+	*task = Task{
+		Version:          TaskVersionLatest,
+		ID:               stored.ID,
+		Name:             stored.Name,
+		Description:      stored.Description,
+		Category:         stored.Category,
+		Tags:             stored.Tags,
+		Archived:         stored.Archived,
+		Position:         stored.Position,
+		CreationDate:     created,
+		Schedule:         stored.Schedule,
+		Kind:             stored.Kind,
+		Unit:             stored.Unit,
+		Target:           stored.Target,
+		GetTime:          time.Now,
+		yearlyTaskAmount: make(YearlyTaskAmount),
+		currentStrike:    stored.Stats.CurrentStrike,
+	}
+
+	if task.ID == "" {
+		task.ID = newID()
+	}
+
+	for _, completion := range stored.History.Completions {
+		date, err := parseDate(completion)
+		if err != nil {
+			return err
+		}
+
+		task.addCompletion(date)
+	}
+
+	for day, amount := range stored.History.Amounts {
+		date, err := parseDate(day)
+		if err != nil {
+			return err
+		}
+
+		task.setAmount(date, amount)
+	}
+
+	if task.skippedDays, err = parseDates(stored.History.Skipped); err != nil {
+		return err
+	}
+
+	for _, vacation := range stored.History.Vacations {
+		dates, err := parseDates([]string{vacation.From, vacation.To})
+		if err != nil {
+			return err
+		}
+
+		task.vacations = append(task.vacations, DateRange{From: dates[0], To: dates[1]})
+	}
+
+	for _, pause := range stored.History.Pauses {
+		dates, err := parseDates([]string{pause.From, pause.To})
+		if err != nil {
+			return err
+		}
+
+		task.pauses = append(task.pauses, DateRange{From: dates[0], To: dates[1]})
+	}
+
+	for day, note := range stored.History.Notes {
+		date, err := parseDate(day)
+		if err != nil {
+			return err
+		}
 
-	if task.Version == "" {
-		task.Version = TaskVersionLatest
+		if err := task.SetNoteAt(date, Note{Text: note.Text, Mood: note.Mood}); err != nil {
+			return err
+		}
 	}
 
+	if task.lastTimeCompleted, err = parseDate(stored.Stats.LastCompleted); err != nil {
+		return err
+	}
+
+	task.strikeAllTime = stored.Stats.AllTimeStrike.strike(StrikeTypeInf)
+	task.strikeThisMonth = stored.Stats.MonthStrike.strike(StrikeTypeMonthly)
+	task.strikeThisWeek = stored.Stats.WeekStrike.strike(StrikeTypeWeekly)
+
 	return nil
 }
 
+// amountsJSON returns amounts keyed by "YYYY-MM-DD" day.
+func amountsJSON(yearly YearlyTaskAmount) map[string]int {
+	var amounts map[string]int
+
+	for year, monthly := range yearly {
+		for month, daily := range monthly {
+			for day, amount := range daily {
+				if amounts == nil {
+					amounts = make(map[string]int)
+				}
+
+				amounts[fmt.Sprintf("%04d-%02d-%02d", year, month, day)] = amount
+			}
+		}
+	}
+
+	return amounts
+}
+
+// notesJSON returns notes keyed by "YYYY-MM-DD" day.
+func notesJSON(notes map[string]Note) map[string]noteJSON {
+	var stored map[string]noteJSON
+
+	for day, note := range notes {
+		if stored == nil {
+			stored = make(map[string]noteJSON)
+		}
+
+		stored[day] = noteJSON{Text: note.Text, Mood: note.Mood}
+	}
+
+	return stored
+}
+
+func rangesJSON(dateRanges []DateRange) []rangeJSON {
+	var ranges []rangeJSON
+	for _, dateRange := range dateRanges {
+		ranges = append(ranges, rangeJSON{From: formatDate(dateRange.From), To: formatDate(dateRange.To)})
+	}
+
+	return ranges
+}
+
+// monthlyBestJSON returns best strikes keyed by "YYYY-MM" month.
+func monthlyBestJSON(yearly YearlyBestStrike) map[string]int {
+	var best map[string]int
+
+	for year, monthly := range yearly {
+		for month, strike := range monthly {
+			if best == nil {
+				best = make(map[string]int)
+			}
+
+			best[fmt.Sprintf("%04d-%02d", year, month)] = strike
+		}
+	}
+
+	return best
+}
+
+func newStrikeJSON(s Strike) strikeJSON {
+	return strikeJSON{Count: s.Count, Best: s.Best, LastFinished: formatDate(s.LastFinished)}
+}
+
+// strike converts stored strike into Strike of given type. Invalid
+// last finished date is dropped as strikes are recomputed anyway.
+func (s strikeJSON) strike(t StrikeCountType) Strike {
+	lastFinished, _ := parseDate(s.LastFinished)
+
+	return Strike{Type: t, Count: s.Count, Best: s.Best, LastFinished: lastFinished}
+}
+
+// formatDate returns civil date of t in configured location
+// or empty string for zero time.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return inLocation(t).Format(time.DateOnly)
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, 0, len(dates))
+	for _, date := range dates {
+		formatted = append(formatted, formatDate(date))
+	}
+
+	return formatted
+}
+
+// parseDate parses civil date into noon of that day in configured location
+// so that the day does not change with daylight saving offsets.
+// Empty string gives zero time.
+func parseDate(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, text, Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date: %w", err)
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), middayHour, 0, 0, 0, date.Location()), nil
+}
+
+func parseDates(texts []string) ([]time.Time, error) {
+	var dates []time.Time
+
+	for _, text := range texts {
+		date, err := parseDate(text)
+		if err != nil {
+			return nil, err
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
 func JSONLoadTasks(bytes []byte) (TaskList, error) {
 	taskList := TaskList{}
 	if err := json.Unmarshal(bytes, &taskList); err != nil {
@@ -95,24 +409,32 @@ func JSONLoadTasks(bytes []byte) (TaskList, error) {
 	return taskList, nil
 }
 
+// JSONSaveTasks atomically replaces file with tasks keeping its
+// previous content as one of BackupCount backups.
 func JSONSaveTasks(filename string, tasks TaskList) error {
 	bytes, err := json.MarshalIndent(tasks, "", "  ")
 	if err != nil {
 		return fmt.Errorf("save json failed to marshall tasks(%v): %w", tasks, err)
 	}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("save json failed to create file(%s): %w", filename, err)
+	if err := saveWithBackup(filename, bytes); err != nil {
+		return fmt.Errorf("save json failed: %w", err)
 	}
 
-	defer func() {
-		file.Close()
-	}()
+	return nil
+}
 
-	_, err = file.Write(bytes)
+// JSONReplaceTasks atomically replaces file with tasks without keeping
+// its previous content as backup e.g. when file is saved repeatedly
+// after backup was already made.
+func JSONReplaceTasks(filename string, tasks TaskList) error {
+	bytes, err := json.MarshalIndent(tasks, "", "  ")
 	if err != nil {
-		return fmt.Errorf("save json failed to write to file(%s): %w", filename, err)
+		return fmt.Errorf("save json failed to marshall tasks(%v): %w", tasks, err)
+	}
+
+	if err := writeFileAtomic(filename, bytes); err != nil {
+		return fmt.Errorf("save json failed: %w", err)
 	}
 
 	return nil
@@ -1,6 +1,8 @@
 package habit_test
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -87,3 +89,135 @@ func TestJSONLoadPartialData(t *testing.T) {
 		t.Fatalf("Failed to load tasks from json: %v", err)
 	}
 }
+
+func TestJSONCheckTasks(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`[{"Name":"go for a walk",
+"CreationDate":"2024-03-01T12:00:00+01:00",
+"YearlyTaskCompletion":{"2024":{"3":[
+"2024-03-03T12:00:00+01:00",
+"2024-03-02T12:00:00+01:00",
+"2024-03-03T18:00:00+01:00",
+"2024-03-05T12:00:00+01:00"]}},
+"LastTimeCompleted":"2024-03-05T12:00:00+01:00",
+"CurrentStrike":3,
+"YearlyBestStrike":{"2024":{"3":4}}}]`)
+
+	tasks, issues, err := habit.JSONCheckTasks(data)
+	if err != nil {
+		t.Fatalf("Failed to check tasks: %v", err)
+	}
+
+	expectedFields := []string{"History.Completions", "Stats.CurrentStrike", "Stats.MonthlyBestStrike[2024-03]"}
+	if len(issues) != len(expectedFields) {
+		t.Fatalf("Expected %d issues while got %v", len(expectedFields), issues)
+	}
+
+	for i, field := range expectedFields {
+		if issues[i].Field != field {
+			t.Fatalf("Expected issue %d to be about %s while it is %v", i, field, issues[i])
+		}
+	}
+
+	repaired, err := json.Marshal(tasks)
+	if err != nil {
+		t.Fatalf("Failed to marshal repaired tasks: %v", err)
+	}
+
+	if _, issues, _ := habit.JSONCheckTasks(repaired); len(issues) != 0 {
+		t.Fatalf("Repaired tasks should have no issues got %v", issues)
+	}
+
+	tasks[0].GetTime = func() time.Time { return time.Date(2024, time.March, 5, 20, 0, 0, 0, time.UTC) }
+
+	if err := validateStrike(tasks[0], 1, 2, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestLoadOldBestStrike(t *testing.T) {
+	t.Parallel()
+
+	// files saved by older versions kept strike finished in a month
+	// as its best so it could be longer than a month
+	bytes := []byte(`[{"Version":"v1","Name":"read","Description":"",
+		"CreationDate":"2024-03-01T12:00:00Z",
+		"YearlyTaskCompletion":{"2024":{"3":["2024-03-30T12:00:00Z","2024-03-31T12:00:00Z"],
+			"4":["2024-04-01T12:00:00Z"]}},
+		"LastTimeCompleted":"2024-04-01T12:00:00Z","CurrentStrike":3,
+		"YearlyBestStrike":{"2024":{"3":2,"4":40}},
+		"BestStrikeLastFinished":"2024-04-01T12:00:00Z"}]`)
+
+	tasks, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	if best := tasks[0].MonthBestStrike(2024, time.April); best != 1 {
+		t.Fatalf("Loaded task April best strike is %d when it should be 1", best)
+	}
+
+	if best := tasks[0].YearBestStrike(2024); best != 3 {
+		t.Fatalf("Loaded task 2024 best strike is %d when it should be 3", best)
+	}
+}
+
+func TestTaskIDs(t *testing.T) {
+	t.Parallel()
+
+	tasks := habit.TaskList{habit.NewTask("walk", ""), habit.NewTask("walk", "")}
+	if tasks[0].ID == "" || tasks[0].ID == tasks[1].ID {
+		t.Fatalf("Tasks should have unique ids got %q and %q", tasks[0].ID, tasks[1].ID)
+	}
+
+	tasks[1].Name = "run"
+
+	bytes, err := json.Marshal(tasks)
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	loaded, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	if idx := loaded.IndexByID(tasks[1].ID); idx != 1 || loaded[idx].Name != "run" {
+		t.Fatalf("Renamed task should be found by its id at 1 got %d", idx)
+	}
+
+	duplicated, _ := json.Marshal(habit.TaskList{tasks[0], tasks[0]})
+	if _, err := habit.JSONLoadTasks(duplicated); !errors.Is(err, habit.ErrDuplicateID) {
+		t.Fatalf("Loading tasks with the same id should fail with duplicate id error got %v", err)
+	}
+}
+
+func TestJSONCheckTasksPositions(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)}
+	read, run := habit.WithCustomTime("read", "", dit.Now), habit.WithCustomTime("run", "", dit.Now)
+
+	for range 3 {
+		read.MakeCompleted()
+		dit.AddDay()
+	}
+
+	// file order differs from manual order
+	read.Position, run.Position = 2, 1
+
+	data, err := json.Marshal(habit.TaskList{read, run})
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	tasks, issues, err := habit.JSONCheckTasks(data)
+	if err != nil {
+		t.Fatalf("Failed to check tasks: %v", err)
+	}
+
+	if len(issues) != 0 || tasks[0].Name != "run" {
+		t.Fatalf("Tasks in manual order run,read should have no issues got %s first and %v", tasks[0].Name, issues)
+	}
+}
@@ -0,0 +1,76 @@
+package habit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var ErrConflict = errors.New("conflicting changes")
+
+// MergeTasks merges changes made to base tasks in ours and theirs lists the
+// way three-way merge does. Task changed on one side only is taken from that
+// side, tasks added on either side are kept and tasks deleted on one side are
+// removed unless they were changed on the other one. Names of tasks changed
+// differently on both sides are returned as conflicts and such tasks are
+// taken from ours. Merged list is in manual order by Position, tasks
+// without it keep order of theirs with tasks added in ours put at the end.
+func MergeTasks(base, ours, theirs TaskList) (TaskList, []string) {
+	merged := TaskList{}
+	conflicts := []string{}
+
+	for _, their := range theirs {
+		baseIdx, ourIdx := base.IndexByID(their.ID), ours.IndexByID(their.ID)
+
+		switch {
+		case ourIdx < 0 && baseIdx < 0:
+			// added by them
+			merged = append(merged, their.Clone())
+		case ourIdx < 0 && sameTask(base[baseIdx], their):
+			// deleted by us
+		case ourIdx < 0:
+			conflicts = append(conflicts, their.Name)
+			merged = append(merged, their.Clone())
+		case sameTask(ours[ourIdx], their):
+			merged = append(merged, their.Clone())
+		case baseIdx >= 0 && sameTask(base[baseIdx], their):
+			merged = append(merged, ours[ourIdx].Clone())
+		case baseIdx >= 0 && sameTask(base[baseIdx], ours[ourIdx]):
+			merged = append(merged, their.Clone())
+		default:
+			conflicts = append(conflicts, ours[ourIdx].Name)
+			merged = append(merged, ours[ourIdx].Clone())
+		}
+	}
+
+	for _, our := range ours {
+		if theirs.IndexByID(our.ID) >= 0 {
+			continue
+		}
+
+		baseIdx := base.IndexByID(our.ID)
+
+		switch {
+		case baseIdx < 0:
+			// added by us
+			merged = append(merged, our.Clone())
+		case sameTask(base[baseIdx], our):
+			// deleted by them
+		default:
+			conflicts = append(conflicts, our.Name)
+			merged = append(merged, our.Clone())
+		}
+	}
+
+	merged.SortByPosition()
+
+	return merged, conflicts
+}
+
+// sameTask returns whether both tasks would be saved the same way.
+func sameTask(one, other Task) bool {
+	oneJSON, _ := json.Marshal(one)
+	otherJSON, _ := json.Marshal(other)
+
+	return bytes.Equal(oneJSON, otherJSON)
+}
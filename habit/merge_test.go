@@ -0,0 +1,67 @@
+package habit_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestMergeTasks(t *testing.T) {
+	t.Parallel()
+
+	base := habit.TaskList{
+		habit.NewTask("changed by us", ""),
+		habit.NewTask("changed by them", ""),
+		habit.NewTask("deleted by us", ""),
+		habit.NewTask("deleted by them", ""),
+		habit.NewTask("unchanged", ""),
+	}
+
+	ours, theirs := base.Clone(), base.Clone()
+	ours[0].MakeCompleted()
+	theirs[1].Description = "their description"
+	ours = slices.Delete(ours, 2, 3)
+	theirs = slices.Delete(theirs, 3, 4)
+	ours = append(ours, habit.NewTask("added by us", ""))
+	theirs = append(theirs, habit.NewTask("added by them", ""))
+
+	merged, conflicts := habit.MergeTasks(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts got %v", conflicts)
+	}
+
+	names := []string{}
+	for _, task := range merged {
+		names = append(names, task.Name)
+	}
+
+	expected := []string{"changed by us", "changed by them", "unchanged", "added by them", "added by us"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("Expected merged tasks %v got %v", expected, names)
+	}
+
+	if !merged[0].WasCompletedToday() || merged[1].Description != "their description" {
+		t.Fatal("Merged tasks should keep changes of both sides")
+	}
+}
+
+func TestMergeTasksConflict(t *testing.T) {
+	t.Parallel()
+
+	base := habit.TaskList{habit.NewTask("both changed", ""), habit.NewTask("deleted and changed", "")}
+	ours, theirs := base.Clone(), base.Clone()
+	ours[0].MakeCompleted()
+	theirs[0].Description = "their description"
+	ours = ours[:1]
+	theirs[1].MakeCompleted()
+
+	merged, conflicts := habit.MergeTasks(base, ours, theirs)
+	if !slices.Equal(conflicts, []string{"both changed", "deleted and changed"}) {
+		t.Fatalf("Expected both tasks to conflict got %v", conflicts)
+	}
+
+	if len(merged) != 2 || !merged[0].WasCompletedToday() || !merged[1].WasCompletedToday() {
+		t.Fatalf("Conflicting tasks should be taken from ours and not deleted got %+v", merged)
+	}
+}
@@ -0,0 +1,181 @@
+package habit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported task version")
+	ErrNewerVersion       = errors.New("task was saved by newer habitui version")
+)
+
+// taskVersions lists task json versions from the oldest one.
+// Task of version taskVersions[i] is upgraded by migrations[i].
+//
+//nolint:gochecknoglobals
+var (
+	taskVersions = []string{"v1", "v2"}
+	migrations   = []func([]byte) ([]byte, error){
+		migrateV1ToV2,
+	}
+)
+
+// MigrateJSON upgrades tasks json of any supported version to given version.
+// Stored values are converted as they are so upgraded data can still be
+// checked against completion history.
+func MigrateJSON(bytes []byte, version string) ([]byte, error) {
+	tasks := []json.RawMessage{}
+	if err := json.Unmarshal(bytes, &tasks); err != nil {
+		return nil, fmt.Errorf("migrate json failed to umarshal bytes: %w", err)
+	}
+
+	for i := range tasks {
+		migrated, err := migrateTask(tasks[i], version)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks[i] = migrated
+	}
+
+	migrated, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("migrate json failed to marshal tasks: %w", err)
+	}
+
+	return migrated, nil
+}
+
+// migrateTask upgrades single task json to given version.
+// Task without version is treated as the first version.
+func migrateTask(data []byte, version string) ([]byte, error) {
+	header := struct{ Version string }{}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to read task version: %w", err)
+	}
+
+	if header.Version == "" {
+		header.Version = taskVersions[0]
+	}
+
+	from := slices.Index(taskVersions, header.Version)
+	to := slices.Index(taskVersions, version)
+
+	switch {
+	case to < 0:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, version)
+	case from < 0 && versionNumber(header.Version) > versionNumber(TaskVersionLatest):
+		return nil, fmt.Errorf("%w: task version is %s while the newest supported version is %s",
+			ErrNewerVersion, header.Version, TaskVersionLatest)
+	case from < 0:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, header.Version)
+	case from > to:
+		return nil, fmt.Errorf("%w: can not downgrade task from %s to %s", ErrUnsupportedVersion, header.Version, version)
+	}
+
+	for _, migrate := range migrations[from:to] {
+		var err error
+		if data, err = migrate(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// versionNumber returns number of version in "vN" format or -1 if it is not such version.
+func versionNumber(version string) int {
+	number, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil || !strings.HasPrefix(version, "v") {
+		return -1
+	}
+
+	return number
+}
+
+// taskJSONV1 is task format that stores full timestamps of completions
+// grouped by year and month.
+type taskJSONV1 struct {
+	Version      string
+	Name         string
+	Description  string
+	CreationDate time.Time
+	Schedule     Schedule
+	Kind         TaskKind
+	Unit         string
+	Target       int
+
+	YearlyTaskCompletion   YearlyTaskCompletion
+	YearlyTaskAmount       YearlyTaskAmount
+	SkippedDays            []time.Time
+	Vacations              []DateRange
+	LastTimeCompleted      time.Time
+	CurrentStrike          int
+	BestStrikeThisWeek     int
+	StrikeAllTime          Strike
+	StrikeThisMonth        Strike
+	StrikeThisWeek         Strike
+	YearlyBestStrike       YearlyBestStrike
+	BestStrikeLastFinished time.Time
+}
+
+// migrateV1ToV2 converts timestamps into civil dates, moves history and
+// statistics into their own sections and gives task an ID derived from
+// its name and creation date so that migrating the same file twice gives
+// the same IDs.
+func migrateV1ToV2(data []byte) ([]byte, error) {
+	old := taskJSONV1{}
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, fmt.Errorf("failed to migrate task to v2: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(old.Name + "\x00" + old.CreationDate.Format(time.RFC3339Nano)))
+	stored := taskJSONV2{
+		Version:     "v2",
+		ID:          hex.EncodeToString(hash[:idBytes]),
+		Name:        old.Name,
+		Description: old.Description,
+		Created:     formatDate(old.CreationDate),
+		Kind:        old.Kind,
+		Unit:        old.Unit,
+		Target:      old.Target,
+		Schedule:    old.Schedule,
+		History: historyJSONV2{
+			Completions: []string{},
+			Amounts:     amountsJSON(old.YearlyTaskAmount),
+			Skipped:     formatDates(old.SkippedDays),
+			Vacations:   rangesJSON(old.Vacations),
+		},
+		Stats: statsJSONV2{
+			LastCompleted:     formatDate(old.LastTimeCompleted),
+			CurrentStrike:     old.CurrentStrike,
+			AllTimeStrike:     newStrikeJSON(old.StrikeAllTime),
+			MonthStrike:       newStrikeJSON(old.StrikeThisMonth),
+			WeekStrike:        newStrikeJSON(old.StrikeThisWeek),
+			MonthlyBestStrike: monthlyBestJSON(old.YearlyBestStrike),
+		},
+	}
+
+	for _, year := range slices.Sorted(maps.Keys(old.YearlyTaskCompletion)) {
+		for month := time.January; month <= time.December; month++ {
+			stored.History.Completions = append(stored.History.Completions,
+				formatDates(old.YearlyTaskCompletion[year][month])...)
+		}
+	}
+
+	migrated, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate task to v2: %w", err)
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,109 @@
+package habit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata") //nolint:gochecknoglobals
+
+// taskVersions lists task versions from the oldest, each of them has
+// golden file in testdata/migrations that is result of migrating
+// the previous version file.
+var taskVersions = []string{"v1", "v2"} //nolint:gochecknoglobals
+
+func TestMigrateGolden(t *testing.T) {
+	t.Parallel()
+
+	if latest := taskVersions[len(taskVersions)-1]; latest != habit.TaskVersionLatest {
+		t.Fatalf("Latest version %s has no golden file, add it after %s", habit.TaskVersionLatest, latest)
+	}
+
+	for i := 1; i < len(taskVersions); i++ {
+		from, to := taskVersions[i-1], taskVersions[i]
+		golden := filepath.Join("testdata", "migrations", to+".json")
+
+		input, err := os.ReadFile(filepath.Join("testdata", "migrations", from+".json"))
+		if err != nil {
+			t.Fatalf("Failed to read %s tasks: %v", from, err)
+		}
+
+		migrated, err := habit.MigrateJSON(input, to)
+		if err != nil {
+			t.Fatalf("Failed to migrate tasks from %s to %s: %v", from, to, err)
+		}
+
+		if *updateGolden {
+			if err := os.WriteFile(golden, append(migrated, '\n'), 0o600); err != nil {
+				t.Fatalf("Failed to update golden file: %v", err)
+			}
+		}
+
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("Failed to read golden file: %v", err)
+		}
+
+		if !bytes.Equal(bytes.TrimSpace(migrated), bytes.TrimSpace(expected)) {
+			t.Fatalf("Tasks migrated from %s to %s differ from %s:\n%s", from, to, golden, migrated)
+		}
+	}
+}
+
+func TestMigratedTasksLoadTheSame(t *testing.T) {
+	t.Parallel()
+
+	oldest, err := os.ReadFile(filepath.Join("testdata", "migrations", taskVersions[0]+".json"))
+	if err != nil {
+		t.Fatalf("Failed to read tasks: %v", err)
+	}
+
+	latest, err := os.ReadFile(filepath.Join("testdata", "migrations", habit.TaskVersionLatest+".json"))
+	if err != nil {
+		t.Fatalf("Failed to read tasks: %v", err)
+	}
+
+	var saved [2][]byte
+
+	for i, data := range [][]byte{oldest, latest} {
+		tasks, err := habit.JSONLoadTasks(data)
+		if err != nil {
+			t.Fatalf("Failed to load tasks: %v", err)
+		}
+
+		if saved[i], err = json.Marshal(tasks); err != nil {
+			t.Fatalf("Failed to marshal tasks: %v", err)
+		}
+	}
+
+	if !bytes.Equal(saved[0], saved[1]) {
+		t.Fatalf("Tasks loaded from %s and %s differ:\n%s\n%s", taskVersions[0], habit.TaskVersionLatest, saved[0], saved[1])
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version string
+		err     error
+	}{
+		{"v3", habit.ErrNewerVersion},
+		{"v20", habit.ErrNewerVersion},
+		{"beta", habit.ErrUnsupportedVersion},
+	}
+
+	for _, test := range tests {
+		_, err := habit.JSONLoadTasks([]byte(`[{"Version":"` + test.version + `","Name":"read"}]`))
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Loading task of version %s should fail with %v got %v", test.version, test.err, err)
+		}
+	}
+}
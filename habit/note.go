@@ -0,0 +1,140 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMood  = errors.New("invalid mood")
+	ErrNotCompleted = errors.New("task was not completed at given date")
+)
+
+// maxMood is the best mood rating of a day.
+const maxMood = 5
+
+// Note is a journal entry attached to completion of a day
+// e.g. "ran 5k, knee hurt".
+type Note struct {
+	Text string
+	// Mood is optional rating of the day from 1 to 5 where 0 means no rating.
+	Mood int
+}
+
+// ParseNote parses note in format "[mood |] text" e.g. "4 | ran 5k, knee hurt".
+func ParseNote(text string) (Note, error) {
+	moodText, noteText, found := strings.Cut(text, "|")
+	if !found {
+		return Note{Text: strings.TrimSpace(text)}, nil
+	}
+
+	mood, err := strconv.Atoi(strings.TrimSpace(moodText))
+	if err != nil {
+		return Note{}, fmt.Errorf("%w: expected '[1-%d |] text' got %q", ErrInvalidMood, maxMood, text)
+	}
+
+	note := Note{Text: strings.TrimSpace(noteText), Mood: mood}
+
+	return note, note.Validate()
+}
+
+// Validate checks whether mood is within allowed range.
+func (note Note) Validate() error {
+	if note.Mood < 0 || note.Mood > maxMood {
+		return fmt.Errorf("%w: %d is not in range 1-%d", ErrInvalidMood, note.Mood, maxMood)
+	}
+
+	return nil
+}
+
+// IsEmpty returns whether note has neither text nor mood.
+func (note Note) IsEmpty() bool {
+	return note.Text == "" && note.Mood == 0
+}
+
+func (note Note) String() string {
+	if note.Mood == 0 {
+		return note.Text
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s (mood %d/%d)", note.Text, note.Mood, maxMood))
+}
+
+// NoteAt returns note attached to completion at given date.
+func (task *Task) NoteAt(date time.Time) (Note, bool) {
+	note, exists := task.notes[formatDate(date)]
+
+	return note, exists
+}
+
+// SetNoteAt attaches note to completion at given date replacing previous one.
+// Empty note removes the note. Notes can be attached only to completed days.
+func (task *Task) SetNoteAt(date time.Time, note Note) error {
+	if err := note.Validate(); err != nil {
+		return err
+	}
+
+	if note.IsEmpty() {
+		delete(task.notes, formatDate(date))
+
+		return nil
+	}
+
+	if !task.WasCompletedAt(inLocation(date).Date()) {
+		return fmt.Errorf("%w: %s", ErrNotCompleted, formatDate(date))
+	}
+
+	if task.notes == nil {
+		task.notes = make(map[string]Note)
+	}
+
+	task.notes[formatDate(date)] = note
+
+	return nil
+}
+
+// pruneNotes removes notes of days which are no longer completed.
+func (task *Task) pruneNotes() {
+	for day := range task.notes {
+		date, err := parseDate(day)
+		if err != nil || !task.WasCompletedAt(date.Date()) {
+			delete(task.notes, day)
+		}
+	}
+}
+
+// NoteMatch is a note found by TaskList.SearchNotes.
+type NoteMatch struct {
+	Task string
+	Date string
+	Note Note
+}
+
+func (match NoteMatch) String() string {
+	return fmt.Sprintf("%s %s: %s", match.Date, match.Task, match.Note)
+}
+
+// SearchNotes returns notes which text contains query ignoring case
+// sorted by date. Mood greater than zero returns only notes with that mood.
+func (tasks TaskList) SearchNotes(query string, mood int) []NoteMatch {
+	query = strings.ToLower(query)
+	matches := []NoteMatch{}
+
+	for _, task := range tasks {
+		for _, date := range slices.Sorted(maps.Keys(task.notes)) {
+			note := task.notes[date]
+			if (mood == 0 || note.Mood == mood) && strings.Contains(strings.ToLower(note.Text), query) {
+				matches = append(matches, NoteMatch{Task: task.Name, Date: date, Note: note})
+			}
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b NoteMatch) int { return strings.Compare(a.Date, b.Date) })
+
+	return matches
+}
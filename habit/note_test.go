@@ -0,0 +1,94 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseNote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		note habit.Note
+		err  error
+	}{
+		{"ran 5k", habit.Note{Text: "ran 5k"}, nil},
+		{"4 | ran 5k, knee hurt", habit.Note{Text: "ran 5k, knee hurt", Mood: 4}, nil},
+		{" 5| ", habit.Note{Mood: 5}, nil},
+		{"6 | too good", habit.Note{}, habit.ErrInvalidMood},
+		{"great | day", habit.Note{}, habit.ErrInvalidMood},
+	}
+
+	for _, test := range tests {
+		note, err := habit.ParseNote(test.text)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Parsing %q returned error %v while expected %v", test.text, err, test.err)
+		}
+
+		if test.err == nil && note != test.note {
+			t.Fatalf("Parsing %q returned %+v while expected %+v", test.text, note, test.note)
+		}
+	}
+}
+
+func TestCompletionNotes(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 23, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("run", "", dit.Now)
+	yesterday := dit.Now().AddDate(0, 0, -1)
+
+	if err := task.SetNoteAt(dit.Now(), habit.Note{Text: "ran 5k"}); !errors.Is(err, habit.ErrNotCompleted) {
+		t.Fatalf("Note for day without completion should fail with %v got %v", habit.ErrNotCompleted, err)
+	}
+
+	task.MakeCompleted()
+	task.MakeCompletedAt(yesterday)
+
+	if err := task.SetNoteAt(dit.Now(), habit.Note{Text: "ran 5k, knee hurt", Mood: 3}); err != nil {
+		t.Fatalf("Failed to set note: %v", err)
+	}
+
+	if err := task.SetNoteAt(yesterday, habit.Note{Text: "easy 3k", Mood: 5}); err != nil {
+		t.Fatalf("Failed to set note: %v", err)
+	}
+
+	bytes, _ := json.Marshal(task)
+	loaded := habit.Task{}
+
+	if err := json.Unmarshal(bytes, &loaded); err != nil {
+		t.Fatalf("Failed to load task with notes: %v", err)
+	}
+
+	if note, ok := loaded.NoteAt(dit.Now()); !ok || note.Text != "ran 5k, knee hurt" || note.Mood != 3 {
+		t.Fatalf("Loaded note %+v differs from saved one", note)
+	}
+
+	tasks := habit.TaskList{loaded}
+	if matches := tasks.SearchNotes("KNEE", 0); len(matches) != 1 || matches[0].Date != "2024-05-23" {
+		t.Fatalf("Search for knee returned %v", matches)
+	}
+
+	if matches := tasks.SearchNotes("", 5); len(matches) != 1 || matches[0].Note.Text != "easy 3k" {
+		t.Fatalf("Search for mood 5 returned %v", matches)
+	}
+
+	task.MakeUnCompletedAt(yesterday)
+
+	if _, ok := task.NoteAt(yesterday); ok {
+		t.Fatal("Note should be removed together with completion")
+	}
+
+	if err := task.SetNoteAt(dit.Now(), habit.Note{}); err != nil {
+		t.Fatalf("Failed to remove note: %v", err)
+	}
+
+	if _, ok := task.NoteAt(dit.Now()); ok {
+		t.Fatal("Empty note should remove the note")
+	}
+}
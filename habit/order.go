@@ -0,0 +1,122 @@
+package habit
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// SortMode is an order in which tasks are listed.
+type SortMode int
+
+const (
+	// SortManual lists tasks in order set by the user.
+	SortManual SortMode = iota
+	// SortName lists tasks alphabetically.
+	SortName
+	// SortStreak lists tasks with the longest current strike first.
+	SortStreak
+	// SortCompletion lists tasks with the highest completion rate this month first.
+	SortCompletion
+	// SortNotDone lists tasks not completed today first.
+	SortNotDone
+
+	sortModes
+)
+
+func (mode SortMode) String() string {
+	switch mode {
+	case SortManual:
+		return "manual"
+	case SortName:
+		return "by name"
+	case SortStreak:
+		return "by strike"
+	case SortCompletion:
+		return "by completion"
+	case SortNotDone:
+		return "not done first"
+	case sortModes:
+	}
+
+	return "unknown"
+}
+
+// Next returns sort mode following this one going back to SortManual after the last one.
+func (mode SortMode) Next() SortMode {
+	return (mode + 1) % sortModes
+}
+
+// SortByPosition orders tasks by their Position. Tasks without Position
+// such as new tasks or tasks saved by older versions keep their relative
+// order after positioned ones.
+func (tasks TaskList) SortByPosition() {
+	position := func(task Task) int {
+		if task.Position <= 0 {
+			return math.MaxInt
+		}
+
+		return task.Position
+	}
+
+	slices.SortStableFunc(tasks, func(a, b Task) int { return cmp.Compare(position(a), position(b)) })
+}
+
+// Swap swaps tasks at given indexes in manual order exchanging their
+// Position so that the order is persisted. Tasks without Position or out
+// of order are numbered first while other tasks are left unchanged so
+// swap does not conflict with changes of other tasks made elsewhere.
+func (tasks TaskList) Swap(one, other int) {
+	last := 0
+
+	for idx := range tasks {
+		if tasks[idx].Position <= last {
+			tasks[idx].Position = last + 1
+		}
+
+		last = tasks[idx].Position
+	}
+
+	tasks[one].Position, tasks[other].Position = tasks[other].Position, tasks[one].Position
+	tasks[one], tasks[other] = tasks[other], tasks[one]
+}
+
+// SortedIndexes returns indexes of tasks in order of given sort mode
+// without changing the list. Equal tasks keep manual order.
+func (tasks TaskList) SortedIndexes(mode SortMode) []int {
+	indexes := make([]int, len(tasks))
+	for idx := range indexes {
+		indexes[idx] = idx
+	}
+
+	compare := func(Task, Task) int { return 0 }
+
+	switch mode {
+	case SortName:
+		compare = func(a, b Task) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	case SortStreak:
+		compare = func(a, b Task) int { return cmp.Compare(b.CurrentStrike(), a.CurrentStrike()) }
+	case SortCompletion:
+		compare = func(a, b Task) int {
+			return cmp.Compare(b.CurrentMonthCompletionRate(), a.CurrentMonthCompletionRate())
+		}
+	case SortNotDone:
+		compare = func(a, b Task) int {
+			return cmp.Compare(boolInt(a.WasCompletedToday()), boolInt(b.WasCompletedToday()))
+		}
+	case SortManual, sortModes:
+	}
+
+	slices.SortStableFunc(indexes, func(a, b int) int { return compare(tasks[a], tasks[b]) })
+
+	return indexes
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
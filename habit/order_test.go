@@ -0,0 +1,92 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func taskNames(tasks habit.TaskList, indexes []int) string {
+	names := []string{}
+	for _, idx := range indexes {
+		names = append(names, tasks[idx].Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestManualOrder(t *testing.T) {
+	t.Parallel()
+
+	tasks := habit.TaskList{habit.NewTask("new", ""), habit.NewTask("second", ""), habit.NewTask("first", "")}
+	tasks[1].Position, tasks[2].Position = 2, 1
+
+	bytes, _ := json.Marshal(tasks)
+	loaded := habit.TaskList{}
+
+	if err := json.Unmarshal(bytes, &loaded); err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+
+	if order := taskNames(loaded, []int{0, 1, 2}); order != "first,second,new" {
+		t.Fatalf("Loaded tasks should be in manual order first,second,new got %s", order)
+	}
+
+	loaded.Swap(1, 2)
+
+	if order := taskNames(loaded, []int{0, 1, 2}); order != "first,new,second" || loaded[2].Position != 3 {
+		t.Fatalf("Swapped tasks should be first,new,second with numbered positions got %s %d", order, loaded[2].Position)
+	}
+
+	// once tasks have positions only swapped ones change
+	before := loaded.Clone()
+	loaded.Swap(1, 2)
+
+	if loaded[0].Position != before[0].Position || loaded[1].Position != 2 || loaded[2].Position != 3 {
+		t.Fatalf("Only swapped tasks should change position got %d,%d,%d",
+			loaded[0].Position, loaded[1].Position, loaded[2].Position)
+	}
+
+	merged, conflicts := habit.MergeTasks(before, loaded, before)
+	if len(conflicts) != 0 || taskNames(merged, []int{0, 1, 2}) != "first,second,new" {
+		t.Fatalf("Swap should merge without conflicts got %v", conflicts)
+	}
+}
+
+func TestSortedIndexes(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)}
+	read := habit.WithCustomTime("read", "", dit.Now)
+	run := habit.WithCustomTime("Run", "", dit.Now)
+	write := habit.WithCustomTime("write", "", dit.Now)
+
+	for range 3 {
+		read.MakeCompleted()
+		dit.AddDay()
+	}
+
+	run.MakeCompleted()
+	write.MakeCompletedAt(dit.Now().AddDate(0, 0, -1))
+
+	tasks := habit.TaskList{write, read, run}
+	tests := []struct {
+		mode  habit.SortMode
+		order string
+	}{
+		{habit.SortManual, "write,read,Run"},
+		{habit.SortName, "read,Run,write"},
+		{habit.SortStreak, "read,write,Run"},
+		{habit.SortCompletion, "read,write,Run"},
+		{habit.SortNotDone, "write,read,Run"},
+	}
+
+	for _, test := range tests {
+		if order := taskNames(tasks, tasks.SortedIndexes(test.mode)); order != test.order {
+			t.Fatalf("Tasks sorted %s should be %s got %s", test.mode, test.order, order)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTarget = errors.New("invalid target")
+
+// DailyTaskAmount is a record of amount done for each day of month.
+type DailyTaskAmount map[int]int
+
+// MonthlyTaskAmount keeps a record of DailyTaskAmount for each month.
+type MonthlyTaskAmount map[time.Month]DailyTaskAmount
+
+// YearlyTaskAmount keeps a record of MonthlyTaskAmount for each year.
+type YearlyTaskAmount map[int]MonthlyTaskAmount
+
+// ParseTarget parses daily target in format "<number> [unit]" e.g. "8 glasses".
+// Zero target means the task is a simple done or not done task.
+func ParseTarget(text string) (int, string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, "", nil
+	}
+
+	number, unit, _ := strings.Cut(text, " ")
+
+	target, err := strconv.Atoi(number)
+	if err != nil || target < 0 {
+		return 0, "", fmt.Errorf("%w: expected '<number> [unit]' got %q", ErrInvalidTarget, text)
+	}
+
+	return target, strings.TrimSpace(unit), nil
+}
+
+// SetTarget changes task into quantity task with given daily target and unit.
+// Target equal to zero changes task back to the done or not done kind.
+// Days completed before task became quantity task get amount equal to target
+// so no history is lost, afterwards completions follow the recorded amounts.
+// Quit tasks have no target and their relapses can not become completions.
+func (task *Task) SetTarget(target int, unit string) error {
+	if task.Kind == TaskKindQuit {
+		if target > 0 {
+			return fmt.Errorf("%w: task to quit %q can not have daily target", ErrInvalidTarget, task.Name)
+		}
+
+		return nil
+	}
+
+	if target <= 0 {
+		task.Kind = TaskKindCheck
+		task.Target = 0
+		task.Unit = ""
+		task.yearlyTaskAmount = make(YearlyTaskAmount)
+
+		return nil
+	}
+
+	if task.Kind != TaskKindQuantity {
+		for _, completion := range task.completions() {
+			task.setAmount(completion, target)
+		}
+	}
+
+	task.Kind = TaskKindQuantity
+	task.Target = target
+	task.Unit = unit
+
+	task.RecomputeStats()
+
+	return nil
+}
+
+// TargetString returns task daily target with unit e.g. "8 glasses".
+func (task *Task) TargetString() string {
+	return strings.TrimSpace(fmt.Sprintf("%d %s", task.Target, task.Unit))
+}
+
+// AmountAt returns amount recorded for quantity task at given date.
+func (task *Task) AmountAt(date time.Time) int {
+	date = inLocation(date)
+
+	return task.yearlyTaskAmount[date.Year()][date.Month()][date.Day()]
+}
+
+// AmountToday returns amount recorded for quantity task at current GetTime day.
+func (task *Task) AmountToday() int {
+	return task.AmountAt(task.Now())
+}
+
+// SetAmountAt records amount done at given date, negative amounts are treated as zero.
+// Day becomes completed when amount reaches task Target and stops being completed
+// when amount drops below it. Dates after current GetTime day are ignored.
+func (task *Task) SetAmountAt(date time.Time, amount int) {
+	date = inLocation(date)
+	if task.Kind != TaskKindQuantity || daysBetween(task.Now(), date) > 0 {
+		return
+	}
+
+	amount = max(amount, 0)
+	task.setAmount(date, amount)
+
+	if amount >= task.Target && !task.WasCompletedAt(date.Date()) {
+		task.addCompletion(date)
+	}
+
+	task.RecomputeStats()
+}
+
+// AddAmount adds delta to amount recorded for current GetTime day.
+func (task *Task) AddAmount(delta int) {
+	task.SetAmountAt(task.Now(), task.AmountToday()+delta)
+}
+
+// MonthAmount returns total amount recorded over given month.
+func (task *Task) MonthAmount(year int, month time.Month) int {
+	total := 0
+	for _, amount := range task.yearlyTaskAmount[year][month] {
+		total += amount
+	}
+
+	return total
+}
+
+func (task *Task) setAmount(date time.Time, amount int) {
+	if task.yearlyTaskAmount == nil {
+		task.yearlyTaskAmount = make(YearlyTaskAmount)
+	}
+
+	date = inLocation(date)
+
+	monthly, exists := task.yearlyTaskAmount[date.Year()]
+	if !exists {
+		monthly = make(MonthlyTaskAmount, numberOfMonths)
+		task.yearlyTaskAmount[date.Year()] = monthly
+	}
+
+	daily, exists := monthly[date.Month()]
+	if !exists {
+		daily = make(DailyTaskAmount)
+		monthly[date.Month()] = daily
+	}
+
+	if amount == 0 {
+		delete(daily, date.Day())
+
+		return
+	}
+
+	daily[date.Day()] = amount
+}
+
+// syncQuantityHistory makes completion history of quantity task agree
+// with recorded amounts so that only days that reached Target are completed.
+func (task *Task) syncQuantityHistory() {
+	for _, completion := range task.completions() {
+		if task.AmountAt(completion) < task.Target {
+			task.removeCompletion(completion)
+		}
+	}
+
+	for year, monthly := range task.yearlyTaskAmount {
+		for month, daily := range monthly {
+			for day, amount := range daily {
+				if amount >= task.Target && !task.WasCompletedAt(year, month, day) {
+					task.addCompletion(time.Date(year, month, day, middayHour, 0, 0, 0, task.Now().Location()))
+				}
+			}
+		}
+	}
+}
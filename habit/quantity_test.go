@@ -0,0 +1,153 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text   string
+		target int
+		unit   string
+		err    bool
+	}{
+		{"8 glasses", 8, "glasses", false},
+		{"30", 30, "", false},
+		{" 20 pages of book ", 20, "pages of book", false},
+		{"", 0, "", false},
+		{"many pages", 0, "", true},
+		{"-3 minutes", 0, "", true},
+	}
+
+	for _, test := range tests {
+		target, unit, err := habit.ParseTarget(test.text)
+		if test.err {
+			if !errors.Is(err, habit.ErrInvalidTarget) {
+				t.Fatalf("ParseTarget(%q) should fail with invalid target error got %v", test.text, err)
+			}
+
+			continue
+		}
+
+		if err != nil || target != test.target || unit != test.unit {
+			t.Fatalf("ParseTarget(%q) returned (%d, %q, %v) while expected (%d, %q, nil)",
+				test.text, target, unit, err, test.target, test.unit)
+		}
+	}
+}
+
+func TestQuantityTask(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("drink water", "", dit.Now)
+	task.SetTarget(8, "glasses")
+
+	// day 1: target met, day 2: partial, day 3 and 4: target met
+	for day, glasses := range []int{8, 5, 9, 8} {
+		if day > 0 {
+			dit.AddDay()
+		}
+
+		for range glasses {
+			task.AddAmount(1)
+		}
+	}
+
+	if amount := task.AmountToday(); amount != 8 {
+		t.Fatalf("Task amount today is %d when it should be 8", amount)
+	}
+
+	if task.WasCompletedAt(2024, time.June, 4) {
+		t.Fatal("Partially done day should not count as completed")
+	}
+
+	if err := validateStrike(task, 2, 2, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := validateCompletion(task, 3, 3, 3); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if amount := task.MonthAmount(2024, time.June); amount != 30 {
+		t.Fatalf("Task month amount is %d when it should be 30", amount)
+	}
+
+	// topping up partial day completes it and joins strikes
+	task.SetAmountAt(time.Date(2024, time.June, 4, 21, 0, 0, 0, time.UTC), 8)
+
+	if err := validateStrike(task, 4, 4, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// decreasing today's amount below target breaks today's completion
+	task.AddAmount(-1)
+
+	if task.WasCompletedToday() {
+		t.Fatal("Task should not be completed today with amount below target")
+	}
+
+	// marking as completed fills amount up to target
+	task.MakeCompleted()
+
+	if amount := task.AmountToday(); amount != 8 {
+		t.Fatalf("Task amount today is %d when it should be 8", amount)
+	}
+
+	// raising target keeps only days that reached it
+	task.SetTarget(9, "glasses")
+
+	if err := validateCompletion(task, 1, 1, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestQuantityTaskJSON(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)
+	task := habit.WithCustomTime("read", "", func() time.Time { return now })
+	task.MakeCompleted()
+	// previously completed days keep being completed after becoming quantity task
+	task.SetTarget(20, "pages")
+
+	if amount := task.AmountToday(); amount != 20 || !task.WasCompletedToday() {
+		t.Fatalf("Task should be completed with amount 20 while amount is %d", amount)
+	}
+
+	task.SetAmountAt(now.AddDate(0, 0, -1), 12)
+
+	bytes, err := json.Marshal(habit.TaskList{task})
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	loaded, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	loadedTask := loaded[0]
+	loadedTask.GetTime = task.GetTime
+
+	if loadedTask.Kind != habit.TaskKindQuantity || loadedTask.TargetString() != "20 pages" {
+		t.Fatalf("Loaded task should be quantity task with target '20 pages' got %v %q",
+			loadedTask.Kind, loadedTask.TargetString())
+	}
+
+	if amount := loadedTask.AmountAt(now.AddDate(0, 0, -1)); amount != 12 {
+		t.Fatalf("Loaded amount is %d when it should be 12", amount)
+	}
+
+	if err := validateCompletion(loadedTask, 1, 1, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+}
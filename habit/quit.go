@@ -0,0 +1,81 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTaskHasHistory = errors.New("task has completion history")
+
+// SetQuit changes task into habit to quit when quit is true or back
+// into done or not done task otherwise. Quit tasks have no daily target
+// so any recorded amounts are dropped. Completions of quit task are
+// relapses so kind of task that was already completed can not be changed.
+func (task *Task) SetQuit(quit bool) error {
+	if quit == (task.Kind == TaskKindQuit) {
+		return nil
+	}
+
+	if len(task.completions()) > 0 {
+		return fmt.Errorf("%w: %s", ErrTaskHasHistory, task.Name)
+	}
+
+	if !quit {
+		task.Kind = TaskKindCheck
+		task.RecomputeStats()
+
+		return nil
+	}
+
+	// task is not quit task yet so dropping target can not fail
+	_ = task.SetTarget(0, "")
+	task.Kind = TaskKindQuit
+	task.RecomputeStats()
+
+	return nil
+}
+
+// IsClean returns whether quit task had no relapse at given date.
+// Days before task creation and after current GetTime day are not clean.
+func (task *Task) IsClean(date time.Time) bool {
+	if daysBetween(task.CreationDate, date) < 0 || daysBetween(date, task.Now()) < 0 {
+		return false
+	}
+
+	return !task.WasCompletedAt(inLocation(date).Date())
+}
+
+// cleanDaysStrike returns number of clean days since creation date
+// or the day after the last relapse up to today inclusive.
+func (task *Task) cleanDaysStrike() int {
+	now := task.Now()
+	if task.WasCompletedToday() {
+		return 0
+	}
+
+	if task.lastTimeCompleted.IsZero() {
+		return max(daysBetween(task.CreationDate, now)+1, 0)
+	}
+
+	return max(daysBetween(task.lastTimeCompleted, now), 0)
+}
+
+// longestCleanRun returns the longest run of consecutive clean days
+// between first and last date inclusive.
+func (task *Task) longestCleanRun(first, last time.Time) int {
+	longest, current := 0, 0
+
+	for day := first; daysBetween(day, last) >= 0; day = day.AddDate(0, 0, 1) {
+		if !task.IsClean(day) {
+			current = 0
+
+			continue
+		}
+
+		current++
+		longest = max(longest, current)
+	}
+
+	return longest
+}
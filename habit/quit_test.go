@@ -0,0 +1,135 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestQuitTask(t *testing.T) {
+	t.Parallel()
+
+	// created on Monday 2024-04-01
+	dit := dayIncreasingTime{time.Date(2024, time.April, 1, 20, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("no smoking", "", dit.Now)
+	if err := task.SetQuit(true); err != nil {
+		t.Fatalf("Failed to set quit: %v", err)
+	}
+
+	if err := validateStrike(task, 1, 1, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for range 4 {
+		dit.AddDay()
+	}
+
+	// relapse on Friday after 4 clean days
+	task.MakeCompleted()
+
+	if err := validateStrike(task, 0, 4, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := validateCompletion(task, 1, 1, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for range 6 {
+		dit.AddDay()
+	}
+
+	if err := validateStrike(task, 6, 6, 6); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// forgotten relapse two days ago splits the run
+	task.MakeCompletedAt(dit.CurrentTime.AddDate(0, 0, -2))
+
+	if err := validateStrike(task, 2, 4, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// monthly best counts only days within the month
+	dit.CurrentTime = time.Date(2024, time.May, 10, 20, 0, 0, 0, time.UTC)
+
+	if err := validateStrike(task, 31, 10, 31); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bytes, err := json.Marshal(habit.TaskList{task})
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	loaded, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	loaded[0].GetTime = dit.Now
+
+	if err := validateStrike(loaded[0], 31, 10, 31); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// relapses would become completions
+	if err := task.SetQuit(false); !errors.Is(err, habit.ErrTaskHasHistory) {
+		t.Fatalf("Unsetting quit of task with relapses should fail got %v", err)
+	}
+
+	if task.Kind != habit.TaskKindQuit {
+		t.Fatalf("Task should stay quit task got %v", task.Kind)
+	}
+}
+
+func TestSetQuitWithHistory(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.April, 1, 20, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("smoking", "", dit.Now)
+
+	if err := task.SetQuit(true); err != nil {
+		t.Fatalf("Failed to set quit: %v", err)
+	}
+
+	if err := task.SetQuit(false); err != nil || task.Kind != habit.TaskKindCheck {
+		t.Fatalf("Task without history should become done or not done task got %v, %v", task.Kind, err)
+	}
+
+	task.MakeCompleted()
+
+	// completions would become relapses
+	if err := task.SetQuit(true); !errors.Is(err, habit.ErrTaskHasHistory) {
+		t.Fatalf("Setting quit of completed task should fail got %v", err)
+	}
+
+	if task.Kind != habit.TaskKindCheck || task.CurrentStrike() != 1 {
+		t.Fatalf("Completed task should be unchanged got kind %v strike %d", task.Kind, task.CurrentStrike())
+	}
+}
+
+func TestQuitTaskTarget(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.April, 1, 20, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("smoking", "", dit.Now)
+	_ = task.SetQuit(true)
+	task.MakeCompleted()
+
+	if err := task.SetTarget(8, "cigarettes"); !errors.Is(err, habit.ErrInvalidTarget) {
+		t.Fatalf("Setting target of quit task should fail got %v", err)
+	}
+
+	// relapse stays relapse when target is dropped
+	if err := task.SetTarget(0, ""); err != nil || task.Kind != habit.TaskKindQuit {
+		t.Fatalf("Quit task should stay quit task got %v, %v", task.Kind, err)
+	}
+
+	if err := validateCompletion(task, 1, 1, 1); err != nil {
+		t.Fatal(err.Error())
+	}
+}
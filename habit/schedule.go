@@ -0,0 +1,268 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduleKind describes how often a task is expected to be completed.
+type ScheduleKind int
+
+const (
+	// ScheduleDaily expects completion every day.
+	ScheduleDaily ScheduleKind = iota
+	// ScheduleEveryNDays expects completion at most Interval days after previous one.
+	ScheduleEveryNDays
+	// ScheduleTimesPerWeek expects Times completions during each week (Monday - Sunday).
+	ScheduleTimesPerWeek
+	// ScheduleTimesPerMonth expects Times completions during each calendar month.
+	ScheduleTimesPerMonth
+	// ScheduleWeekdays expects completion on each of the given Weekdays.
+	ScheduleWeekdays
+)
+
+const (
+	daysInWeek        = 7
+	averageDaysInYear = 365
+)
+
+// Schedule describes task recurrence. The zero value is a daily schedule
+// so tasks saved before schedules were introduced keep their behaviour.
+type Schedule struct {
+	Kind     ScheduleKind
+	Interval int            `json:",omitempty"`
+	Times    int            `json:",omitempty"`
+	Weekdays []time.Weekday `json:",omitempty"`
+}
+
+// DailySchedule returns schedule expecting task completion every day.
+func DailySchedule() Schedule {
+	return Schedule{Kind: ScheduleDaily}
+}
+
+// EveryNDaysSchedule returns schedule expecting task completion every n days.
+func EveryNDaysSchedule(n int) Schedule {
+	return Schedule{Kind: ScheduleEveryNDays, Interval: n}
+}
+
+// TimesPerWeekSchedule returns schedule expecting given number of completions each week.
+func TimesPerWeekSchedule(times int) Schedule {
+	return Schedule{Kind: ScheduleTimesPerWeek, Times: times}
+}
+
+// TimesPerMonthSchedule returns schedule expecting given number of completions each month.
+func TimesPerMonthSchedule(times int) Schedule {
+	return Schedule{Kind: ScheduleTimesPerMonth, Times: times}
+}
+
+// WeekdaysSchedule returns schedule expecting completion on each of given days.
+func WeekdaysSchedule(days ...time.Weekday) Schedule {
+	days = slices.Clone(days)
+	slices.Sort(days)
+
+	return Schedule{Kind: ScheduleWeekdays, Weekdays: slices.Compact(days)}
+}
+
+// Validate checks whether schedule parameters make sense for its kind.
+func (s Schedule) Validate() error {
+	switch s.Kind {
+	case ScheduleDaily:
+		return nil
+	case ScheduleEveryNDays:
+		if s.Interval < 1 {
+			return fmt.Errorf("%w: interval must be positive got %d", ErrInvalidSchedule, s.Interval)
+		}
+	case ScheduleTimesPerWeek:
+		if s.Times < 1 || s.Times > daysInWeek {
+			return fmt.Errorf("%w: times per week must be in range 1-7 got %d", ErrInvalidSchedule, s.Times)
+		}
+	case ScheduleTimesPerMonth:
+		if s.Times < 1 || s.Times > 31 {
+			return fmt.Errorf("%w: times per month must be in range 1-31 got %d", ErrInvalidSchedule, s.Times)
+		}
+	case ScheduleWeekdays:
+		if len(s.Weekdays) == 0 {
+			return fmt.Errorf("%w: at least one week day is required", ErrInvalidSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %d", ErrInvalidSchedule, s.Kind)
+	}
+
+	return nil
+}
+
+// String returns schedule in the format accepted by ParseSchedule.
+func (s Schedule) String() string {
+	switch s.Kind {
+	case ScheduleDaily:
+		return "daily"
+	case ScheduleEveryNDays:
+		return fmt.Sprintf("every %d days", s.Interval)
+	case ScheduleTimesPerWeek:
+		return fmt.Sprintf("%d/week", s.Times)
+	case ScheduleTimesPerMonth:
+		return fmt.Sprintf("%d/month", s.Times)
+	case ScheduleWeekdays:
+		names := make([]string, 0, len(s.Weekdays))
+		for _, d := range s.Weekdays {
+			names = append(names, strings.ToLower(d.String()[:3]))
+		}
+
+		return strings.Join(names, ",")
+	}
+
+	return "unknown"
+}
+
+// ParseSchedule parses schedule from its text form. Accepted formats are:
+// "daily", "every N days", "N/week", "N/month" and comma separated
+// list of week days e.g. "mon,wed,fri".
+func ParseSchedule(text string) (Schedule, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var schedule Schedule
+
+	switch {
+	case text == "daily" || text == "":
+		schedule = DailySchedule()
+	case strings.HasPrefix(text, "every "):
+		fields := strings.Fields(text)
+		//// nolint:gomnd // "every", number and "days"
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "day") {
+			return Schedule{}, fmt.Errorf("%w: expected 'every N days' got %q", ErrInvalidSchedule, text)
+		}
+
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+
+		schedule = EveryNDaysSchedule(n)
+	case strings.HasSuffix(text, "/week"), strings.HasSuffix(text, "/month"):
+		count, period, _ := strings.Cut(text, "/")
+
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			return Schedule{}, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+
+		schedule = TimesPerWeekSchedule(n)
+		if period == "month" {
+			schedule = TimesPerMonthSchedule(n)
+		}
+	default:
+		days := []time.Weekday{}
+
+		for _, name := range strings.Split(text, ",") {
+			day, err := parseWeekday(strings.TrimSpace(name))
+			if err != nil {
+				return Schedule{}, err
+			}
+
+			days = append(days, day)
+		}
+
+		schedule = WeekdaysSchedule(days...)
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return Schedule{}, err
+	}
+
+	return schedule, nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	//// nolint:gomnd // three letter day abbreviation
+	if len(name) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), name) {
+				return d, nil
+			}
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("%w: unknown week day %q", ErrInvalidSchedule, name)
+}
+
+// IsDue returns whether given date is a day on which the schedule
+// expects completion. For schedules that are not bound to specific
+// days every day is treated as due.
+func (s Schedule) IsDue(date time.Time) bool {
+	if s.Kind == ScheduleWeekdays {
+		return slices.Contains(s.Weekdays, date.Weekday())
+	}
+
+	return true
+}
+
+// Expected returns number of completions the schedule expects over
+// the days from first up to last date inclusive.
+func (s Schedule) Expected(first, last time.Time) int {
+	return s.expected(first, last, nil)
+}
+
+// expected returns number of completions the schedule expects over the days
+// from first up to last date inclusive leaving out days for which skipped
+// returns true.
+func (s Schedule) expected(first, last time.Time, skipped func(time.Time) bool) int {
+	days, due := 0, 0
+
+	for day := first; daysBetween(day, last) >= 0; day = day.AddDate(0, 0, 1) {
+		if skipped != nil && skipped(day) {
+			continue
+		}
+
+		days++
+
+		if s.IsDue(day) {
+			due++
+		}
+	}
+
+	switch s.Kind {
+	case ScheduleDaily:
+		return days
+	case ScheduleEveryNDays:
+		interval := max(s.Interval, 1)
+
+		return (days + interval - 1) / interval
+	case ScheduleTimesPerWeek:
+		return int(math.Round(float64(days*s.Times) / daysInWeek))
+	case ScheduleTimesPerMonth:
+		return int(math.Round(float64(days*s.Times*numberOfMonths) / averageDaysInYear))
+	case ScheduleWeekdays:
+		return due
+	}
+
+	return days
+}
+
+// daysBetween returns number of calendar days from one date to other
+// in configured location ignoring time of day and daylight saving changes.
+func daysBetween(from, to time.Time) int {
+	y, m, d := inLocation(from).Date()
+	fromDate := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = inLocation(to).Date()
+	toDate := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	return int(toDate.Sub(fromDate).Hours() / 24) //nolint:gomnd
+}
+
+// weekStart returns Monday of the week that given date belongs to.
+func weekStart(date time.Time) time.Time {
+	weekDay := int(date.Weekday())
+	// counting Sunday as seventh day of the week
+	if weekDay == 0 {
+		weekDay = daysInWeek
+	}
+
+	return date.AddDate(0, 0, 1-weekDay)
+}
@@ -0,0 +1,208 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		expected habit.Schedule
+		err      bool
+	}{
+		{"daily", habit.DailySchedule(), false},
+		{"", habit.DailySchedule(), false},
+		{"every 3 days", habit.EveryNDaysSchedule(3), false},
+		{"Every 1 day", habit.EveryNDaysSchedule(1), false},
+		{"3/week", habit.TimesPerWeekSchedule(3), false},
+		{"10/month", habit.TimesPerMonthSchedule(10), false},
+		{"fri,mon,wed", habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday), false},
+		{"saturday, sun", habit.WeekdaysSchedule(time.Saturday, time.Sunday), false},
+		{"every 0 days", habit.Schedule{}, true},
+		{"8/week", habit.Schedule{}, true},
+		{"mo,we", habit.Schedule{}, true},
+		{"sometimes", habit.Schedule{}, true},
+	}
+
+	for _, test := range tests {
+		schedule, err := habit.ParseSchedule(test.text)
+		if test.err {
+			if !errors.Is(err, habit.ErrInvalidSchedule) {
+				t.Fatalf("ParseSchedule(%q) should fail with invalid schedule error got %v", test.text, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) failed: %v", test.text, err)
+		}
+
+		if schedule.String() != test.expected.String() {
+			t.Fatalf("ParseSchedule(%q) returned %q while expected %q", test.text, schedule, test.expected)
+		}
+
+		if reparsed, _ := habit.ParseSchedule(schedule.String()); reparsed.String() != schedule.String() {
+			t.Fatalf("Schedule %q does not survive String and ParseSchedule round trip got %q", schedule, reparsed)
+		}
+	}
+}
+
+func TestScheduleStrike(t *testing.T) {
+	t.Parallel()
+
+	// 2024-04-01 is Monday
+	monday := time.Date(2024, time.April, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule habit.Schedule
+		// days offsets from monday at which task is completed
+		completions []int
+		// day offset from monday at which strike is checked
+		checkAt        int
+		expectedStrike int
+	}{
+		{"daily continued", habit.DailySchedule(), []int{0, 1, 2}, 3, 3},
+		{"daily broken", habit.DailySchedule(), []int{0, 1, 2}, 4, 0},
+		{"daily restarted", habit.DailySchedule(), []int{0, 1, 3}, 3, 1},
+		{"every 3 days continued", habit.EveryNDaysSchedule(3), []int{0, 3, 6}, 9, 3},
+		{"every 3 days broken", habit.EveryNDaysSchedule(3), []int{0, 3, 6}, 10, 0},
+		{"every 3 days restarted", habit.EveryNDaysSchedule(3), []int{0, 4, 7}, 7, 2},
+		{
+			"gym mon wed fri over weekend",
+			habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday),
+			[]int{0, 2, 4, 7}, 9, 4,
+		},
+		{
+			"gym mon wed fri missed wednesday",
+			habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday),
+			[]int{0, 4}, 4, 1,
+		},
+		{
+			"gym mon wed fri not yet missed",
+			habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday),
+			[]int{0, 2}, 4, 2,
+		},
+		{
+			"gym mon wed fri broken",
+			habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday),
+			[]int{0, 2}, 5, 0,
+		},
+		{"3 per week kept", habit.TimesPerWeekSchedule(3), []int{0, 3, 6, 8}, 13, 4},
+		{"3 per week target not met", habit.TimesPerWeekSchedule(3), []int{0, 3, 8}, 8, 1},
+		{"3 per week broken by empty week", habit.TimesPerWeekSchedule(3), []int{0, 3, 6}, 14, 0},
+		{"2 per month kept", habit.TimesPerMonthSchedule(2), []int{0, 20, 35}, 45, 3},
+		{"2 per month target not met", habit.TimesPerMonthSchedule(2), []int{0, 35}, 45, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			current := monday
+			task := habit.WithCustomTime(test.name, "", func() time.Time { return current })
+			task.Schedule = test.schedule
+
+			for _, offset := range test.completions {
+				current = monday.AddDate(0, 0, offset)
+				task.MakeCompleted()
+			}
+
+			current = monday.AddDate(0, 0, test.checkAt)
+
+			if strike := task.CurrentStrike(); strike != test.expectedStrike {
+				t.Fatalf("Task strike is %d when it should be %d", strike, test.expectedStrike)
+			}
+		})
+	}
+}
+
+func TestScheduleWeekAcrossMonths(t *testing.T) {
+	t.Parallel()
+
+	// week from Monday 2024-01-29 ends on Sunday 4th of February
+	dit := dayIncreasingTime{time.Date(2024, time.January, 29, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("gym", "", dit.Now)
+	task.Schedule = habit.TimesPerWeekSchedule(3)
+
+	for range 3 {
+		task.MakeCompleted()
+		dit.AddDay()
+	}
+
+	dit.CurrentTime = time.Date(2024, time.February, 2, 12, 0, 0, 0, time.UTC)
+	if completed := task.CurrentWeekCompletion(); completed != 3 {
+		t.Fatalf("Week completion on 2nd of February should count January days got %d", completed)
+	}
+
+	// week target was reached so strike is kept in the next week
+	dit.CurrentTime = time.Date(2024, time.February, 5, 12, 0, 0, 0, time.UTC)
+	if strike := task.CurrentStrike(); strike != 3 {
+		t.Fatalf("Strike should be kept after week crossing months got %d", strike)
+	}
+}
+
+func TestScheduleTargets(t *testing.T) {
+	t.Parallel()
+
+	// 2024-02-14 Wednesday of a leap year
+	now := time.Date(2024, time.February, 14, 12, 0, 0, 0, time.UTC)
+	task := habit.WithCustomTime("targets", "", func() time.Time { return now })
+
+	tests := []struct {
+		schedule habit.Schedule
+		week     int
+		month    int
+		year     int
+	}{
+		{habit.DailySchedule(), 7, 29, 366},
+		{habit.EveryNDaysSchedule(2), 4, 15, 183},
+		{habit.TimesPerWeekSchedule(3), 3, 12, 157},
+		{habit.TimesPerMonthSchedule(4), 1, 4, 48},
+		{habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday), 3, 12, 157},
+	}
+
+	for _, test := range tests {
+		task.Schedule = test.schedule
+
+		week, month, year := task.AllTarget()
+		if week != test.week || month != test.month || year != test.year {
+			t.Fatalf("Schedule %q targets are (%d, %d, %d) while expected (%d, %d, %d)",
+				test.schedule, week, month, year, test.week, test.month, test.year)
+		}
+	}
+}
+
+func TestScheduleJSON(t *testing.T) {
+	t.Parallel()
+
+	task := habit.NewTask("gym", "lift")
+	task.Schedule = habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday)
+
+	bytes, err := json.Marshal(habit.TaskList{task})
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	loaded, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	if got := loaded[0].Schedule.String(); got != task.Schedule.String() {
+		t.Fatalf("Loaded schedule is %q while it should be %q", got, task.Schedule)
+	}
+
+	_, err = habit.JSONLoadTasks([]byte(`[{"Name":"bad","Schedule":{"Kind":1,"Interval":0}}]`))
+	if !errors.Is(err, habit.ErrInvalidSchedule) {
+		t.Fatalf("Loading task with invalid schedule should fail got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package habit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// DateRange is a range of days from From up to To inclusive.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseDateRange parses range in format "YYYY-MM-DD YYYY-MM-DD" in given location.
+// Single date is treated as one day range.
+func ParseDateRange(text string, loc *time.Location) (DateRange, error) {
+	fields := strings.Fields(text)
+	//// nolint:gomnd // from and optional to date
+	if len(fields) == 0 || len(fields) > 2 {
+		return DateRange{}, fmt.Errorf("%w: expected 'YYYY-MM-DD [YYYY-MM-DD]' got %q", ErrInvalidDateRange, text)
+	}
+
+	dates := make([]time.Time, 0, len(fields))
+
+	for _, field := range fields {
+		date, err := time.ParseInLocation(time.DateOnly, field, loc)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("%w: %w", ErrInvalidDateRange, err)
+		}
+
+		dates = append(dates, date)
+	}
+
+	dateRange := DateRange{From: dates[0], To: dates[len(dates)-1]}
+	if daysBetween(dateRange.From, dateRange.To) < 0 {
+		return DateRange{}, fmt.Errorf("%w: %s is after %s", ErrInvalidDateRange, fields[0], fields[1])
+	}
+
+	return dateRange, nil
+}
+
+// Contains returns whether date day is within the range.
+func (r DateRange) Contains(date time.Time) bool {
+	return daysBetween(r.From, date) >= 0 && daysBetween(date, r.To) >= 0
+}
+
+// Equal returns whether both ranges cover the same days.
+func (r DateRange) Equal(other DateRange) bool {
+	return AreSameDates(r.From, other.From) && AreSameDates(r.To, other.To)
+}
+
+func (r DateRange) String() string {
+	return r.From.Format(time.DateOnly) + " " + r.To.Format(time.DateOnly)
+}
+
+// ToggleVacation adds vacation range to every task in the list or removes
+// it from every task if any of them already has the same range. During
+// vacation all tasks are paused as if each day was skipped.
+func (tasks TaskList) ToggleVacation(vacation DateRange) {
+	remove := slices.ContainsFunc(tasks.Vacations(), vacation.Equal)
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		task.vacations = slices.DeleteFunc(task.vacations, vacation.Equal)
+		if !remove {
+			task.vacations = append(task.vacations, vacation)
+		}
+
+		task.RecomputeStats()
+	}
+}
+
+// Vacations returns vacation ranges of any task in the list.
+func (tasks TaskList) Vacations() []DateRange {
+	vacations := []DateRange{}
+
+	for _, task := range tasks {
+		for _, vacation := range task.vacations {
+			if !slices.ContainsFunc(vacations, vacation.Equal) {
+				vacations = append(vacations, vacation)
+			}
+		}
+	}
+
+	return vacations
+}
+
+// WithVacations returns task with vacations of the list added so that
+// task created after vacation was set is paused during it as well.
+func (tasks TaskList) WithVacations(task Task) Task {
+	task.vacations = slices.Clone(task.vacations)
+
+	for _, vacation := range tasks.Vacations() {
+		if !slices.ContainsFunc(task.vacations, vacation.Equal) {
+			task.vacations = append(task.vacations, vacation)
+		}
+	}
+
+	task.RecomputeStats()
+
+	return task
+}
+
+// Vacations returns vacation ranges during which the task is paused.
+func (task *Task) Vacations() []DateRange {
+	return task.vacations
+}
+
+// IsSkipped returns whether task was excused at given date either by
+// skipping the day, by vacation or by pause. Skipped days neither break nor extend
+// strikes and are not expected by task Schedule.
+func (task *Task) IsSkipped(date time.Time) bool {
+	return slices.ContainsFunc(task.skippedDays, func(d time.Time) bool { return AreSameDates(d, date) }) ||
+		slices.ContainsFunc(task.vacations, func(r DateRange) bool { return r.Contains(date) }) ||
+		task.isPausedAt(date)
+}
+
+// SkipAt marks given date as skipped removing its completion if there was any.
+func (task *Task) SkipAt(date time.Time) {
+	if task.IsSkipped(date) {
+		return
+	}
+
+	task.removeCompletion(date)
+
+	if task.Kind == TaskKindQuantity {
+		task.setAmount(date, 0)
+	}
+
+	idx, _ := slices.BinarySearchFunc(task.skippedDays, date, func(d, t time.Time) int { return d.Compare(t) })
+	task.skippedDays = slices.Insert(task.skippedDays, idx, date)
+
+	task.RecomputeStats()
+}
+
+// UnSkipAt removes skip mark from given date. Vacation days stay skipped.
+func (task *Task) UnSkipAt(date time.Time) {
+	task.skippedDays = slices.DeleteFunc(task.skippedDays, func(d time.Time) bool { return AreSameDates(d, date) })
+
+	task.RecomputeStats()
+}
+
+// skippedBetween returns number of skipped days after first and before last date.
+func (task *Task) skippedBetween(first, last time.Time) int {
+	skipped := 0
+
+	for day := first.AddDate(0, 0, 1); daysBetween(day, last) > 0; day = day.AddDate(0, 0, 1) {
+		if task.IsSkipped(day) {
+			skipped++
+		}
+	}
+
+	return skipped
+}
+
+// expected returns number of completions expected by task Schedule over the days
+// from first up to last date inclusive leaving out skipped days.
+func (task *Task) expected(first, last time.Time) int {
+	return task.Schedule.expected(first, last, task.IsSkipped)
+}
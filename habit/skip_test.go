@@ -0,0 +1,205 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseDateRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		days int
+		err  bool
+	}{
+		{"2024-07-01 2024-07-14", 14, false},
+		{"2024-07-01", 1, false},
+		{"2024-07-14 2024-07-01", 0, true},
+		{"2024-07-01 2024-07-02 2024-07-03", 0, true},
+		{"first of july", 0, true},
+	}
+
+	for _, test := range tests {
+		dateRange, err := habit.ParseDateRange(test.text, time.UTC)
+		if test.err {
+			if !errors.Is(err, habit.ErrInvalidDateRange) {
+				t.Fatalf("ParseDateRange(%q) should fail with invalid date range error got %v", test.text, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("ParseDateRange(%q) failed: %v", test.text, err)
+		}
+
+		days := 0
+		for day := dateRange.From.AddDate(0, 0, -3); day.Before(dateRange.To.AddDate(0, 0, 3)); day = day.AddDate(0, 0, 1) {
+			if dateRange.Contains(day) {
+				days++
+			}
+		}
+
+		if days != test.days {
+			t.Fatalf("ParseDateRange(%q) range contains %d days while it should %d", test.text, days, test.days)
+		}
+	}
+}
+
+func TestSkippedDays(t *testing.T) {
+	t.Parallel()
+
+	// Monday
+	dit := dayIncreasingTime{time.Date(2024, time.April, 1, 18, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("meditate", "", dit.Now)
+
+	task.MakeCompleted()
+	dit.AddDay()
+	task.MakeCompleted()
+	dit.AddDay()
+	// sick on Wednesday and Thursday
+	task.SkipAt(dit.CurrentTime)
+	task.SkipAt(dit.CurrentTime.AddDate(0, 0, 1))
+	dit.AddDay()
+	dit.AddDay()
+
+	// on Friday strike is still kept but not extended
+	if err := validateStrike(task, 2, 2, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	task.MakeCompleted()
+
+	if err := validateStrike(task, 3, 3, 3); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// skipped days are not expected
+	if week, month, _ := task.AllTarget(); week != 5 || month != 28 {
+		t.Fatalf("Task targets should be 5 for week and 28 for month got %d and %d", week, month)
+	}
+
+	// skipping completed day removes completion
+	task.SkipAt(dit.CurrentTime)
+
+	if task.WasCompletedToday() || !task.IsSkipped(dit.CurrentTime) {
+		t.Fatal("Task should be skipped and not completed today")
+	}
+
+	// completing skipped day removes skip mark
+	task.MakeCompleted()
+
+	if !task.WasCompletedToday() || task.IsSkipped(dit.CurrentTime) {
+		t.Fatal("Task should be completed and not skipped today")
+	}
+
+	task.UnSkipAt(time.Date(2024, time.April, 4, 0, 0, 0, 0, time.UTC))
+
+	if err := validateStrike(task, 1, 2, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestVacation(t *testing.T) {
+	t.Parallel()
+
+	// Monday
+	dit := dayIncreasingTime{time.Date(2024, time.July, 1, 18, 0, 0, 0, time.UTC)}
+	tasks := habit.TaskList{
+		habit.WithCustomTime("walk", "", dit.Now),
+		habit.WithCustomTime("gym", "", dit.Now),
+		habit.WithCustomTime("swim", "", dit.Now),
+	}
+	tasks[1].Schedule = habit.WeekdaysSchedule(time.Monday, time.Wednesday, time.Friday)
+	tasks[2].Schedule = habit.TimesPerWeekSchedule(2)
+
+	for i := range tasks {
+		tasks[i].MakeCompleted()
+	}
+
+	dit.AddDay()
+	tasks[0].MakeCompleted()
+	tasks[2].MakeCompleted()
+
+	// two weeks of vacation from Wednesday
+	vacation, _ := habit.ParseDateRange("2024-07-03 2024-07-16", time.UTC)
+	tasks.ToggleVacation(vacation)
+
+	// back on Wednesday 17th
+	dit.CurrentTime = time.Date(2024, time.July, 17, 18, 0, 0, 0, time.UTC)
+
+	for i := range tasks {
+		tasks[i].MakeCompleted()
+	}
+
+	for i, expected := range []int{3, 2, 3} {
+		if strike := tasks[i].CurrentStrike(); strike != expected {
+			t.Fatalf("Task %q strike is %d when it should be %d", tasks[i].Name, strike, expected)
+		}
+	}
+
+	bytes, err := json.Marshal(tasks)
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	loaded, err := habit.JSONLoadTasks(bytes)
+	if err != nil {
+		t.Fatalf("Failed to load tasks from json: %v", err)
+	}
+
+	for i := range loaded {
+		loaded[i].GetTime = dit.Now
+		if !loaded[i].IsSkipped(time.Date(2024, time.July, 10, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("Loaded task %q should be on vacation", loaded[i].Name)
+		}
+	}
+
+	// toggling the same range ends vacation
+	loaded.ToggleVacation(vacation)
+
+	for i := range loaded {
+		if strike := loaded[i].CurrentStrike(); strike != 1 {
+			t.Fatalf("Task %q strike is %d when it should be 1 without vacation", loaded[i].Name, strike)
+		}
+	}
+}
+
+func TestToggleVacationNewTask(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.July, 1, 20, 0, 0, 0, time.UTC)}
+	vacation, _ := habit.ParseDateRange("2024-07-03 2024-07-16", time.UTC)
+
+	tasks := habit.TaskList{habit.WithCustomTime("a", "", dit.Now)}
+	tasks.ToggleVacation(vacation)
+
+	// task created after vacation was set gets it too
+	tasks = append(tasks, tasks.WithVacations(habit.WithCustomTime("b", "", dit.Now)))
+	if vacations := tasks[1].Vacations(); len(vacations) != 1 || !vacations[0].Equal(vacation) {
+		t.Fatalf("New task should have vacation %v got %v", vacation, vacations)
+	}
+
+	// task without the range does not turn toggle into adding it
+	tasks = append(tasks, habit.WithCustomTime("c", "", dit.Now))
+	tasks.ToggleVacation(vacation)
+
+	for _, task := range tasks {
+		if vacations := task.Vacations(); len(vacations) != 0 {
+			t.Fatalf("Task %q should have no vacation got %v", task.Name, vacations)
+		}
+	}
+
+	tasks.ToggleVacation(vacation)
+
+	for _, task := range tasks {
+		if vacations := task.Vacations(); len(vacations) != 1 {
+			t.Fatalf("Task %q should have vacation got %v", task.Name, vacations)
+		}
+	}
+}
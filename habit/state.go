@@ -0,0 +1,61 @@
+package habit
+
+import (
+	"slices"
+	"time"
+)
+
+// Pause pauses task from today until Resume is called. Paused days
+// are skipped so they neither break nor extend strikes.
+func (task *Task) Pause() {
+	if task.IsPaused() {
+		return
+	}
+
+	task.pauses = append(task.pauses, DateRange{From: task.Now()})
+	task.RecomputeStats()
+}
+
+// Resume ends pause of the task so that today is expected again.
+// Pause started today is removed as it covers no days.
+func (task *Task) Resume() {
+	if !task.IsPaused() {
+		return
+	}
+
+	last := len(task.pauses) - 1
+	if yesterday := task.Now().AddDate(0, 0, -1); daysBetween(task.pauses[last].From, yesterday) >= 0 {
+		task.pauses[last].To = yesterday
+	} else {
+		task.pauses = slices.Delete(task.pauses, last, last+1)
+	}
+
+	task.RecomputeStats()
+}
+
+// IsPaused returns whether task is paused now.
+func (task *Task) IsPaused() bool {
+	return len(task.pauses) > 0 && task.pauses[len(task.pauses)-1].To.IsZero()
+}
+
+// Pauses returns ranges during which task was paused. Range of
+// ongoing pause has zero To.
+func (task *Task) Pauses() []DateRange {
+	return task.pauses
+}
+
+// isPausedAt returns whether task was paused at given date.
+func (task *Task) isPausedAt(date time.Time) bool {
+	return slices.ContainsFunc(task.pauses, func(r DateRange) bool {
+		if r.To.IsZero() {
+			return daysBetween(r.From, date) >= 0
+		}
+
+		return r.Contains(date)
+	})
+}
+
+// Active returns tasks which are not archived.
+func (tasks TaskList) Active() TaskList {
+	return slices.DeleteFunc(slices.Clone(tasks), func(task Task) bool { return task.Archived })
+}
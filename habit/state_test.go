@@ -0,0 +1,81 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestPauseKeepsStrike(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("run", "", dit.Now)
+
+	for range 3 {
+		task.MakeCompleted()
+		dit.AddDay()
+	}
+
+	task.Pause()
+
+	for range 4 {
+		dit.AddDay()
+	}
+
+	if !task.IsPaused() || task.CurrentStrike() != 3 {
+		t.Fatalf("Paused task should keep strike 3 got %d (paused %v)", task.CurrentStrike(), task.IsPaused())
+	}
+
+	task.Resume()
+	task.MakeCompleted()
+
+	if task.IsPaused() || task.CurrentStrike() != 4 {
+		t.Fatalf("Resumed task should continue strike to 4 got %d (paused %v)", task.CurrentStrike(), task.IsPaused())
+	}
+
+	if pauses := task.Pauses(); len(pauses) != 1 || pauses[0].String() != "2024-05-23 2024-05-26" {
+		t.Fatalf("Pauses are %v while expected single pause 2024-05-23 2024-05-26", pauses)
+	}
+
+	task.Pause()
+	task.Resume()
+
+	if len(task.Pauses()) != 1 {
+		t.Fatalf("Pause resumed the same day should be removed got %v", task.Pauses())
+	}
+}
+
+func TestArchivedAndPausedJSON(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 20, 12, 0, 0, 0, time.UTC)}
+	paused := habit.WithCustomTime("run", "", dit.Now)
+	paused.Category = "health"
+	paused.Pause()
+
+	archived := habit.WithCustomTime("read", "", dit.Now)
+	archived.Category = "learning"
+	archived.Archived = true
+
+	bytes, _ := json.Marshal(habit.TaskList{paused, archived})
+	tasks := habit.TaskList{}
+
+	if err := json.Unmarshal(bytes, &tasks); err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+
+	if !tasks[0].IsPaused() || !tasks[1].Archived {
+		t.Fatalf("Loaded tasks lost their state: paused %v archived %v", tasks[0].IsPaused(), tasks[1].Archived)
+	}
+
+	if active := tasks.Active(); len(active) != 1 || active[0].Name != "run" {
+		t.Fatalf("Active tasks are %v while expected only run", active)
+	}
+
+	if categories := tasks.Categories(); len(categories) != 1 || categories[0] != "health" {
+		t.Fatalf("Categories of archived tasks should be left out got %q", categories)
+	}
+}
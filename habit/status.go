@@ -0,0 +1,38 @@
+package habit
+
+// TodayStatus summarizes habits to be done today. Quit tasks and tasks
+// that are skipped, paused or not scheduled today are not counted as
+// done or pending but their strikes are taken into account.
+type TodayStatus struct {
+	Done         int
+	Pending      int
+	Total        int
+	Strike       int
+	PendingNames []string
+}
+
+// TodayStatus returns status of not archived tasks at current day.
+func (tasks TaskList) TodayStatus() TodayStatus {
+	status := TodayStatus{PendingNames: []string{}}
+
+	for _, task := range tasks.Active() {
+		status.Strike = max(status.Strike, task.CurrentStrike())
+		now := task.Now()
+
+		switch {
+		case task.Kind == TaskKindQuit:
+			continue
+		case task.WasCompletedToday():
+			status.Done++
+		case task.IsSkipped(now) || !task.Schedule.IsDue(now):
+			continue
+		default:
+			status.Pending++
+			status.PendingNames = append(status.PendingNames, task.Name)
+		}
+
+		status.Total++
+	}
+
+	return status
+}
@@ -0,0 +1,49 @@
+package habit_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestTodayStatus(t *testing.T) {
+	t.Parallel()
+
+	// Monday
+	timer := &dayIncreasingTime{time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC)}
+	tasks := habit.TaskList{}
+
+	for _, name := range []string{"done", "pending", "skipped", "weekend", "quit", "archived"} {
+		task := habit.WithCustomTime(name, "", timer.Now)
+		task.CreationDate = timer.Now().AddDate(0, 0, -10)
+		tasks = append(tasks, task)
+	}
+
+	for range 3 {
+		timer.CurrentTime = timer.CurrentTime.AddDate(0, 0, -1)
+		tasks[0].MakeCompleted()
+	}
+
+	timer.CurrentTime = timer.CurrentTime.AddDate(0, 0, 3)
+	tasks[0].MakeCompleted()
+	tasks[2].SkipAt(timer.Now())
+	tasks[3].Schedule = habit.WeekdaysSchedule(time.Saturday, time.Sunday)
+	_ = tasks[4].SetQuit(true)
+	tasks[5].Archived = true
+
+	status := tasks.TodayStatus()
+	if status.Done != 1 || status.Pending != 1 || status.Total != 2 {
+		t.Fatalf("Expected 1 of 2 habits done got %+v", status)
+	}
+
+	if !slices.Equal(status.PendingNames, []string{"pending"}) {
+		t.Fatalf("Expected only pending habit to be pending got %v", status.PendingNames)
+	}
+
+	// quit task is clean for 11 days
+	if status.Strike != 11 {
+		t.Fatalf("Expected longest strike to be 11 got %d", status.Strike)
+	}
+}
@@ -21,16 +21,23 @@ func NewStrike(t StrikeCountType) Strike {
 	return Strike{Type: t}
 }
 
-// Update updates strike data upon completion.
-func (s *Strike) Update(completeDate time.Time) {
+// add records completion at given date. Continued tells whether completion
+// extends strike that ended at LastFinished. Monthly and weekly strikes
+// restart together with their best when completion starts new period.
+func (s *Strike) add(completeDate time.Time, continued bool) {
 	if AreSameDates(completeDate, s.LastFinished) {
 		return
 	}
 
-	if !s.IsContinued(completeDate) {
-		s.Count = 1
-	} else if AreSameDates(completeDate, s.LastFinished.AddDate(0, 0, 1)) {
+	samePeriod := s.samePeriod(completeDate)
+	if !samePeriod {
+		s.Best = 0
+	}
+
+	if continued && samePeriod {
 		s.Count++
+	} else {
+		s.Count = 1
 	}
 
 	if s.Count > s.Best {
@@ -40,28 +47,32 @@ func (s *Strike) Update(completeDate time.Time) {
 	s.LastFinished = completeDate
 }
 
-// Downgrade updates strike data when task is unmarked from completion.
-func (s *Strike) Downgrade(prevCompletion time.Time) {
-	s.Count--
-	s.LastFinished = prevCompletion
-}
+// samePeriod returns whether date is in the same month or ISO week as
+// LastFinished. Infinite strike has single never ending period.
+func (s Strike) samePeriod(date time.Time) bool {
+	switch s.Type {
+	case StrikeTypeInf:
+	case StrikeTypeMonthly:
+		return date.Year() == s.LastFinished.Year() && date.Month() == s.LastFinished.Month()
+	case StrikeTypeWeekly:
+		dateYear, dateWeek := date.ISOWeek()
+		lastYear, lastWeek := s.LastFinished.ISOWeek()
 
-// IsContinued returns whether strike is broken assuming date is today
-// meaning there was over 1 day break from finishing it.
-func (s Strike) IsContinued(date time.Time) bool {
-	out := AreSameDates(s.LastFinished, date) ||
-		AreSameDates(date.AddDate(0, 0, -1), s.LastFinished)
+		return dateYear == lastYear && dateWeek == lastWeek
+	}
+
+	return true
+}
 
+// periodStart returns first day of the period that date belongs to.
+func (s Strike) periodStart(date time.Time) time.Time {
 	switch s.Type {
 	case StrikeTypeInf:
-		break
 	case StrikeTypeMonthly:
-		out = out && date.Month() == s.LastFinished.Month()
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
 	case StrikeTypeWeekly:
-		_, dateWeek := date.ISOWeek()
-		_, lastFinishWeek := s.LastFinished.ISOWeek()
-		out = out && dateWeek == lastFinishWeek
+		return weekStart(date)
 	}
 
-	return out
+	return time.Time{}
 }
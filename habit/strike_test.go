@@ -0,0 +1,98 @@
+package habit_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestStrikePeriods(t *testing.T) {
+	t.Parallel()
+
+	// Friday 2024-12-27
+	dit := dayIncreasingTime{time.Date(2024, time.December, 27, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("stretch", "", dit.Now)
+
+	// up to Sunday 2024-12-29
+	for range 3 {
+		task.MakeCompleted()
+		dit.AddDay()
+	}
+
+	// Monday 2024-12-30 is in first ISO week of 2025
+	task.MakeCompleted()
+
+	if week, month, best := task.CurrentWeekStrike(), task.CurrentMonthStrike(), task.BestStrike(); week != 1 ||
+		month != 4 || best != 4 {
+		t.Fatalf("Task week, month and best strikes are %d, %d, %d when they should be 1, 4, 4", week, month, best)
+	}
+
+	// up to Sunday 2025-01-05
+	for range 6 {
+		dit.AddDay()
+		task.MakeCompleted()
+	}
+
+	if week, month, best := task.CurrentWeekStrike(), task.CurrentMonthStrike(), task.BestStrike(); week != 7 ||
+		month != 5 || best != 10 {
+		t.Fatalf("Task week, month and best strikes are %d, %d, %d when they should be 7, 5, 10", week, month, best)
+	}
+}
+
+func TestTaskPeriodStrikes(t *testing.T) {
+	t.Parallel()
+
+	// Thursday
+	dit := dayIncreasingTime{time.Date(2024, time.May, 23, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("stretch", "", dit.Now)
+
+	// Thursday up to Tuesday 2024-05-28
+	for range 6 {
+		task.MakeCompleted()
+		dit.AddDay()
+	}
+
+	// Wednesday not completed yet
+	if week, month, best := task.CurrentWeekStrike(), task.CurrentMonthStrike(), task.BestStrike(); week != 2 ||
+		month != 6 || best != 6 {
+		t.Fatalf("Task week, month and best strikes are %d, %d, %d when they should be 2, 6, 6", week, month, best)
+	}
+
+	// Saturday 2024-06-01 after strike was broken
+	dit.CurrentTime = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	task.MakeCompleted()
+
+	if week, month, best := task.CurrentWeekStrike(), task.CurrentMonthStrike(), task.BestStrike(); week != 1 ||
+		month != 1 || best != 6 {
+		t.Fatalf("Task week, month and best strikes are %d, %d, %d when they should be 1, 1, 6", week, month, best)
+	}
+
+	bytes, err := json.Marshal(habit.TaskList{task})
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	type strike struct {
+		Count        int
+		Best         int
+		LastFinished string
+	}
+
+	stored := []struct {
+		Stats struct {
+			AllTimeStrike strike
+			MonthStrike   strike
+			WeekStrike    strike
+		}
+	}{}
+	if err := json.Unmarshal(bytes, &stored); err != nil {
+		t.Fatalf("Failed to unmarshal stored strikes: %v", err)
+	}
+
+	if stats := stored[0].Stats; stats.AllTimeStrike.Best != 6 || stats.MonthStrike.Count != 1 ||
+		stats.WeekStrike.LastFinished != "2024-06-01" {
+		t.Fatalf("Stored strikes %+v are not persisted", stats)
+	}
+}
@@ -0,0 +1,126 @@
+package habit
+
+import (
+	"slices"
+	"strings"
+)
+
+// ParseLabels parses task category and tags in format
+// "category[: tag, tag]" e.g. "health: morning, outdoor".
+// Labels are lower cased and duplicated tags are dropped.
+func ParseLabels(text string) (string, []string) {
+	categoryText, tagsText, _ := strings.Cut(text, ":")
+	category := strings.ToLower(strings.TrimSpace(categoryText))
+
+	var tags []string
+
+	for _, tag := range strings.FieldsFunc(tagsText, func(r rune) bool { return r == ',' }) {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return category, tags
+}
+
+// LabelsString returns task category and tags in format parsed by ParseLabels.
+func (task *Task) LabelsString() string {
+	if len(task.Tags) == 0 {
+		return task.Category
+	}
+
+	return task.Category + ": " + strings.Join(task.Tags, ", ")
+}
+
+// HasLabel returns whether task category or one of its tags is equal to label.
+func (task *Task) HasLabel(label string) bool {
+	return task.Category == label || slices.Contains(task.Tags, label)
+}
+
+// Categories returns sorted categories of not archived tasks. Empty
+// category of uncategorized tasks is put last if there are any.
+func (tasks TaskList) Categories() []string {
+	categories := []string{}
+	uncategorized := false
+
+	for _, task := range tasks.Active() {
+		switch {
+		case task.Category == "":
+			uncategorized = true
+		case !slices.Contains(categories, task.Category):
+			categories = append(categories, task.Category)
+		}
+	}
+
+	slices.Sort(categories)
+
+	if uncategorized {
+		categories = append(categories, "")
+	}
+
+	return categories
+}
+
+// Labels returns sorted categories and tags used by not archived tasks.
+func (tasks TaskList) Labels() []string {
+	labels := []string{}
+
+	for _, task := range tasks.Active() {
+		for _, label := range append([]string{task.Category}, task.Tags...) {
+			if label != "" && !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	slices.Sort(labels)
+
+	return labels
+}
+
+// CategoryStats are completion statistics summed over not archived
+// tasks of a category. Quit tasks count only in Tasks as their
+// completions are relapses.
+type CategoryStats struct {
+	Category        string
+	Tasks           int
+	CompletedToday  int
+	WeekCompletion  int
+	WeekTarget      int
+	MonthCompletion int
+	MonthTarget     int
+}
+
+// CategoryStats returns statistics of each category in Categories order.
+func (tasks TaskList) CategoryStats() []CategoryStats {
+	categories := tasks.Categories()
+	stats := make([]CategoryStats, len(categories))
+
+	for idx, category := range categories {
+		stats[idx].Category = category
+
+		for _, task := range tasks.Active() {
+			if task.Category != category {
+				continue
+			}
+
+			stats[idx].Tasks++
+
+			if task.Kind == TaskKindQuit {
+				continue
+			}
+
+			weekTarget, monthTarget, _ := task.AllTarget()
+			stats[idx].WeekTarget += weekTarget
+			stats[idx].MonthTarget += monthTarget
+			stats[idx].WeekCompletion += task.CurrentWeekCompletion()
+			stats[idx].MonthCompletion += task.CurrentMonthCompletion()
+
+			if task.WasCompletedToday() {
+				stats[idx].CompletedToday++
+			}
+		}
+	}
+
+	return stats
+}
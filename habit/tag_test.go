@@ -0,0 +1,74 @@
+package habit_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestParseLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		category string
+		tags     []string
+	}{
+		{"Health", "health", nil},
+		{"health: Morning, outdoor, morning", "health", []string{"morning", "outdoor"}},
+		{": evening", "", []string{"evening"}},
+		{"", "", nil},
+	}
+
+	for _, test := range tests {
+		category, tags := habit.ParseLabels(test.text)
+		if category != test.category || !slices.Equal(tags, test.tags) {
+			t.Fatalf("Parsing %q returned %q %v while expected %q %v", test.text, category, tags, test.category, test.tags)
+		}
+	}
+}
+
+func TestCategoryStats(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 22, 12, 0, 0, 0, time.UTC)}
+	run := habit.WithCustomTime("run", "", dit.Now)
+	run.Category, run.Tags = "health", []string{"outdoor"}
+	walk := habit.WithCustomTime("walk", "", dit.Now)
+	walk.Category, walk.Tags = "health", []string{"outdoor", "morning"}
+	smoke := habit.WithCustomTime("smoking", "", dit.Now)
+	smoke.Category = "health"
+	_ = smoke.SetQuit(true)
+	read := habit.WithCustomTime("read", "", dit.Now)
+
+	run.MakeCompletedAt(dit.Now().AddDate(0, 0, -1))
+	run.MakeCompleted()
+	walk.MakeCompleted()
+	smoke.MakeCompleted()
+
+	tasks := habit.TaskList{read, run, walk, smoke}
+
+	if categories := tasks.Categories(); !slices.Equal(categories, []string{"health", ""}) {
+		t.Fatalf("Categories are %q while expected health and uncategorized", categories)
+	}
+
+	if labels := tasks.Labels(); !slices.Equal(labels, []string{"health", "morning", "outdoor"}) {
+		t.Fatalf("Labels are %q", labels)
+	}
+
+	stats := tasks.CategoryStats()
+	expected := habit.CategoryStats{
+		Category: "health", Tasks: 3, CompletedToday: 2,
+		WeekCompletion: 3, WeekTarget: 14, MonthCompletion: 3, MonthTarget: 62,
+	}
+
+	if stats[0] != expected {
+		t.Fatalf("Category stats are %+v while expected %+v", stats[0], expected)
+	}
+
+	if stats[1].Category != "" || stats[1].Tasks != 1 || stats[1].CompletedToday != 0 {
+		t.Fatalf("Uncategorized stats are %+v", stats[1])
+	}
+}
@@ -1,33 +1,133 @@
 package habit
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
 	"slices"
+	"strings"
 	"time"
 )
 
-const TaskVersionLatest = "v1"
+const TaskVersionLatest = "v2"
+
+var (
+	ErrDuplicateID   = errors.New("duplicate task id")
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrAmbiguousTask = errors.New("task name is ambiguous")
+)
 
 // TaskList is a slice of tasks.
 type TaskList []Task
 
+// IndexByID returns index of task with given ID or -1 if there is no such task.
+func (tasks TaskList) IndexByID(id string) int {
+	return slices.IndexFunc(tasks, func(task Task) bool { return task.ID == id })
+}
+
+// Find returns index of task with given ID, name or unique name prefix.
+// Names are compared ignoring case.
+func (tasks TaskList) Find(query string) (int, error) {
+	if idx := tasks.IndexByID(query); idx >= 0 {
+		return idx, nil
+	}
+
+	if idx := slices.IndexFunc(tasks, func(task Task) bool { return strings.EqualFold(task.Name, query) }); idx >= 0 {
+		return idx, nil
+	}
+
+	found := -1
+
+	for idx, task := range tasks {
+		if !strings.HasPrefix(strings.ToLower(task.Name), strings.ToLower(query)) {
+			continue
+		}
+
+		if found >= 0 {
+			return -1, fmt.Errorf("%w: %q matches %q and %q", ErrAmbiguousTask, query, tasks[found].Name, task.Name)
+		}
+
+		found = idx
+	}
+
+	if found < 0 {
+		return -1, fmt.Errorf("%w: %q", ErrTaskNotFound, query)
+	}
+
+	return found, nil
+}
+
+// Clone returns deep copy of every task in the list.
+func (tasks TaskList) Clone() TaskList {
+	clone := make(TaskList, len(tasks))
+	for idx := range tasks {
+		clone[idx] = tasks[idx].Clone()
+	}
+
+	return clone
+}
+
+// TaskKind describes how task completion is tracked.
+type TaskKind int
+
+const (
+	// TaskKindCheck is a task that is either done or not for a day.
+	TaskKindCheck TaskKind = iota
+	// TaskKindQuantity is a task where amount is recorded for each day
+	// and the day is completed once amount reaches task Target.
+	TaskKindQuantity
+	// TaskKindQuit is a bad habit to break where completion records a relapse
+	// and strike counts clean days since creation or the last relapse.
+	TaskKindQuit
+)
+
 // Task is an occurring event that has its own name identifier.
-// Each task can be completed once a day.
+// Each task can be completed once a day and is expected to be
+// completed according to its Schedule.
 type Task struct {
-	Version      string
-	Name         string
-	Description  string
+	Version string
+	// ID identifies task and does not change when task is renamed.
+	ID          string
+	Name        string
+	Description string
+	// Category groups tasks e.g. health, work or learning
+	// while Tags are additional labels used for filtering.
+	Category string
+	Tags     []string
+	// Archived tasks are hidden from the list but keep their history.
+	Archived bool
+	// Position is place of the task in manual order starting from 1
+	// where 0 means task was not placed yet.
+	Position     int
 	CreationDate time.Time
-	GetTime      func() time.Time `json:"-"`
+	Schedule     Schedule
+	Kind         TaskKind
+	// Unit and Target are used by TaskKindQuantity tasks only
+	// e.g. 8 glasses, 20 pages or 30 minutes a day.
+	Unit    string
+	Target  int
+	GetTime func() time.Time `json:"-"`
 
 	yearlyTaskCompletion YearlyTaskCompletion
-	lastTimeCompleted    time.Time
-	currentStrike        int
-	// TODO: CurrentMonthBestStrike actually shows best strike ever that was finished in
-	// current month. I think I would like it to show best strikes in current month
-	// in a sense that it is value from 0-31.
-	strikeThisMonth        Strike
-	yearlyBestStrike       YearlyBestStrike
-	bestStrikeLastFinished time.Time
+	yearlyTaskAmount     YearlyTaskAmount
+	skippedDays          []time.Time
+	vacations            []DateRange
+	pauses               []DateRange
+	// notes are keyed by "YYYY-MM-DD" day of completion they belong to.
+	notes             map[string]Note
+	lastTimeCompleted time.Time
+	currentStrike     int
+	// strike trackers rebuilt from history with strikes counted over
+	// all time, within month and within ISO week of their last completion.
+	strikeAllTime   Strike
+	strikeThisMonth Strike
+	strikeThisWeek  Strike
+	// yearlyBestStrike keeps the longest strike contained within each month
+	// and bestStrikeInYear the longest strike contained within each year.
+	yearlyBestStrike YearlyBestStrike
+	bestStrikeInYear map[int]int
 }
 
 // NewTask creates new task based on name and description with default get time function set to time.Now.
@@ -42,18 +142,55 @@ func WithCustomTime(name, description string, getTime func() time.Time) Task {
 	}
 
 	return Task{
-		Version:                TaskVersionLatest,
-		Name:                   name,
-		Description:            description,
-		CreationDate:           getTime(),
-		GetTime:                getTime,
-		yearlyTaskCompletion:   make(YearlyTaskCompletion),
-		lastTimeCompleted:      time.Time{},
-		currentStrike:          0,
-		strikeThisMonth:        Strike{},
-		yearlyBestStrike:       make(YearlyBestStrike),
-		bestStrikeLastFinished: time.Time{},
+		Version:              TaskVersionLatest,
+		ID:                   newID(),
+		Name:                 name,
+		Description:          description,
+		CreationDate:         civilTime(getTime()),
+		Schedule:             DailySchedule(),
+		GetTime:              getTime,
+		yearlyTaskCompletion: make(YearlyTaskCompletion),
+		yearlyTaskAmount:     make(YearlyTaskAmount),
+		lastTimeCompleted:    time.Time{},
+		currentStrike:        0,
+		strikeAllTime:        NewStrike(StrikeTypeInf),
+		strikeThisMonth:      NewStrike(StrikeTypeMonthly),
+		strikeThisWeek:       NewStrike(StrikeTypeWeekly),
+		yearlyBestStrike:     make(YearlyBestStrike),
+		bestStrikeInYear:     make(map[int]int),
+	}
+}
+
+// Clone returns deep copy of the task so that changing one of them
+// does not change history of the other.
+func (task *Task) Clone() Task {
+	clone := *task
+	clone.Tags = slices.Clone(task.Tags)
+	clone.Schedule.Weekdays = slices.Clone(task.Schedule.Weekdays)
+	clone.skippedDays = slices.Clone(task.skippedDays)
+	clone.vacations = slices.Clone(task.vacations)
+	clone.pauses = slices.Clone(task.pauses)
+	clone.notes = maps.Clone(task.notes)
+	clone.yearlyTaskCompletion = make(YearlyTaskCompletion, len(task.yearlyTaskCompletion))
+	clone.yearlyTaskAmount = make(YearlyTaskAmount, len(task.yearlyTaskAmount))
+
+	for year, monthly := range task.yearlyTaskCompletion {
+		clone.yearlyTaskCompletion[year] = make(MonthlyTaskCompletion, len(monthly))
+		for month, completions := range monthly {
+			clone.yearlyTaskCompletion[year][month] = slices.Clone(completions)
+		}
+	}
+
+	for year, monthly := range task.yearlyTaskAmount {
+		clone.yearlyTaskAmount[year] = make(MonthlyTaskAmount, len(monthly))
+		for month, daily := range monthly {
+			clone.yearlyTaskAmount[year][month] = maps.Clone(daily)
+		}
 	}
+
+	clone.RecomputeStats()
+
+	return clone
 }
 
 // LastTimeCompleted returns last date when task completion was done.
@@ -65,92 +202,207 @@ func (task *Task) LastTimeCompleted() time.Time {
 // Date is added to completion history if it wasn't completed this day yet.
 // This method also updates statistics information such as day streak number.
 func (task *Task) MakeCompleted() {
-	now := task.GetTime()
+	task.MakeCompletedAt(task.Now())
+}
 
-	if task.WasCompletedToday() {
+// MakeUnCompleted makes reverts task completion for current day.
+func (task *Task) MakeUnCompleted() {
+	if !task.WasCompletedToday() {
 		return
 	}
 
-	if task.lastTimeCompleted.Year() != now.Year() {
-		initializeDateMaps(
-			task.yearlyTaskCompletion,
-			now.Year(),
-			now.Month(), func() []time.Time {
-				longestHalfMonthUpper := 16
-				init := make([]time.Time, 0, longestHalfMonthUpper)
-				init = append(init, now)
+	task.MakeUnCompletedAt(task.lastTimeCompleted)
+}
 
-				return init
-			})
+// MakeCompletedAt marks task as completed at given date which can be in the past.
+// Completion is inserted into history keeping it sorted and statistics are
+// recalculated. Dates after current GetTime day are ignored.
+// For quantity tasks the day amount is raised to the task Target.
+func (task *Task) MakeCompletedAt(date time.Time) {
+	date = inLocation(date)
+	if daysBetween(task.Now(), date) > 0 || task.WasCompletedAt(date.Date()) {
+		return
+	}
 
-		initializeDateMaps(task.yearlyBestStrike, now.Year(),
-			now.Month(), func() int { return 1 })
+	if task.Kind == TaskKindQuantity {
+		task.setAmount(date, max(task.AmountAt(date), task.Target))
 	}
 
-	if task.lastTimeCompleted.IsZero() {
-		task.lastTimeCompleted = now
-		task.currentStrike = 1
-		task.bestStrikeLastFinished = now
+	task.skippedDays = slices.DeleteFunc(task.skippedDays, func(d time.Time) bool { return AreSameDates(d, date) })
+	task.addCompletion(date)
+	task.RecomputeStats()
+}
 
+// MakeUnCompletedAt removes task completion at given date from history
+// and recalculates statistics. For quantity tasks the day amount is cleared.
+func (task *Task) MakeUnCompletedAt(date time.Time) {
+	date = inLocation(date)
+	if !task.removeCompletion(date) {
 		return
 	}
 
-	if !task.IsStrikeContinued() {
-		task.currentStrike = 1
+	if task.Kind == TaskKindQuantity {
+		task.setAmount(date, 0)
 	}
 
-	if AreSameDates(now, task.lastTimeCompleted.AddDate(0, 0, 1)) {
-		task.currentStrike++
+	task.RecomputeStats()
+}
+
+// addCompletion inserts date into completion history keeping it sorted.
+func (task *Task) addCompletion(date time.Time) {
+	if task.yearlyTaskCompletion == nil {
+		task.yearlyTaskCompletion = make(YearlyTaskCompletion)
+	}
+
+	completionsYear, exists := task.yearlyTaskCompletion[date.Year()]
+	if !exists {
+		completionsYear = make(MonthlyTaskCompletion, numberOfMonths)
+		task.yearlyTaskCompletion[date.Year()] = completionsYear
+	}
+
+	completions := completionsYear[date.Month()]
+	idx, _ := slices.BinarySearchFunc(completions, date, func(c, d time.Time) int { return c.Compare(d) })
+	completionsYear[date.Month()] = slices.Insert(completions, idx, date)
+}
+
+// removeCompletion removes completion at given date from history
+// and returns whether there was any.
+func (task *Task) removeCompletion(date time.Time) bool {
+	completionsYear, exists := task.yearlyTaskCompletion[date.Year()]
+	if !exists {
+		return false
 	}
 
-	monthBestStrike := task.yearlyBestStrike[now.Year()][now.Month()]
+	completions := completionsYear[date.Month()]
 
-	if task.currentStrike > monthBestStrike {
-		task.yearlyBestStrike[now.Year()][now.Month()] = task.currentStrike
-		task.bestStrikeLastFinished = now
+	idx := slices.IndexFunc(completions, func(c time.Time) bool { return AreSameDates(c, date) })
+	if idx < 0 {
+		return false
 	}
 
-	task.lastTimeCompleted = now
+	completionsYear[date.Month()] = slices.Delete(completions, idx, idx+1)
 
-	completionsThisYear := task.yearlyTaskCompletion[now.Year()]
-	completionsThisMonth := completionsThisYear[now.Month()]
+	return true
+}
 
-	if len(completionsThisMonth) == 0 {
-		completionsThisYear[now.Month()] = append(completionsThisMonth, now)
+// RecomputeStats rebuilds every statistic derived from completion history
+// such as last completion date, current strike and best monthly strikes.
+// History itself is normalized to be sorted with at most one completion a day.
+// It is called after each history change and when task is loaded so stored
+// counters never drift from the completions they are computed from.
+func (task *Task) RecomputeStats() {
+	task.normalizeHistory()
+
+	if task.Kind == TaskKindQuantity {
+		task.syncQuantityHistory()
+	}
+
+	task.pruneNotes()
+
+	task.lastTimeCompleted = time.Time{}
+	task.currentStrike = 0
+	task.yearlyBestStrike = make(YearlyBestStrike)
+	task.bestStrikeInYear = make(map[int]int)
+	task.strikeAllTime = NewStrike(StrikeTypeInf)
+	task.strikeThisMonth = NewStrike(StrikeTypeMonthly)
+	task.strikeThisWeek = NewStrike(StrikeTypeWeekly)
+
+	if task.Kind == TaskKindQuit {
+		// clean days strikes depend on current day so they are computed on demand
+		if completions := task.completions(); len(completions) > 0 {
+			task.lastTimeCompleted = completions[len(completions)-1]
+		}
 
 		return
 	}
 
-	if lastComplete := completionsThisMonth[len(completionsThisMonth)-1]; !AreSameDates(now, lastComplete) {
-		completionsThisYear[now.Month()] = append(completionsThisMonth, now)
+	// runs within month and year restart at their first day so that
+	// best monthly strike is at most number of days in month
+	yearRun := 0
+
+	for _, completion := range task.completions() {
+		last := task.lastTimeCompleted
+		continued := task.streakContinues(last, completion)
+
+		if continued {
+			task.currentStrike++
+		} else {
+			task.currentStrike = 1
+		}
+
+		task.strikeAllTime.add(completion, continued)
+		task.strikeThisMonth.add(completion, continued)
+		task.strikeThisWeek.add(completion, continued)
+
+		if continued && last.Year() == completion.Year() {
+			yearRun++
+		} else {
+			yearRun = 1
+		}
+
+		monthlyStrikes, exists := task.yearlyBestStrike[completion.Year()]
+		if !exists {
+			monthlyStrikes = make(MonthlyBestStrike, numberOfMonths)
+			task.yearlyBestStrike[completion.Year()] = monthlyStrikes
+		}
+
+		if monthRun := task.strikeThisMonth.Count; monthRun > monthlyStrikes[completion.Month()] {
+			monthlyStrikes[completion.Month()] = monthRun
+		}
+
+		task.bestStrikeInYear[completion.Year()] = max(task.bestStrikeInYear[completion.Year()], yearRun)
+		task.lastTimeCompleted = completion
 	}
 }
 
-// MakeUnCompleted makes reverts task completion for current day.
-func (task *Task) MakeUnCompleted() {
-	if !task.WasCompletedToday() {
-		return
+// normalizeHistory sorts completions in each month and removes
+// completions of days that were already completed.
+func (task *Task) normalizeHistory() {
+	if task.yearlyTaskCompletion == nil {
+		task.yearlyTaskCompletion = make(YearlyTaskCompletion)
 	}
 
-	complDate := task.lastTimeCompleted
-	task.lastTimeCompleted = time.Time{}
+	for year, completionsYear := range task.yearlyTaskCompletion {
+		for month, completions := range completionsYear {
+			// completions stored with different offset can belong to other
+			// month in configured location so they are moved there
+			kept := completions[:0]
 
-	if monthlyCompletions, exists := task.yearlyTaskCompletion[complDate.Year()]; exists {
-		monthly := monthlyCompletions[complDate.Month()]
-		if completionNum := len(monthly); completionNum > 0 && monthly[completionNum-1].Equal(complDate) {
-			monthlyCompletions[complDate.Month()] = monthly[:completionNum-1]
+			for _, completion := range completions {
+				completion = inLocation(completion)
+				if completion.Year() == year && completion.Month() == month {
+					kept = append(kept, completion)
 
-			if completionNum > 1 {
-				task.lastTimeCompleted = monthly[len(monthly)-2]
+					continue
+				}
+
+				task.addCompletion(completion)
 			}
+
+			completionsYear[month] = kept
 		}
 	}
 
-	task.currentStrike--
+	for _, completionsYear := range task.yearlyTaskCompletion {
+		for month, completions := range completionsYear {
+			slices.SortFunc(completions, func(c, d time.Time) int { return c.Compare(d) })
+			completionsYear[month] = slices.CompactFunc(completions, AreSameDates)
+		}
+	}
+}
+
+// completions returns whole task completion history sorted from the oldest.
+func (task *Task) completions() []time.Time {
+	years := slices.Sorted(maps.Keys(task.yearlyTaskCompletion))
+	all := make([]time.Time, 0, len(years)*numberOfMonths)
 
-	if task.bestStrikeLastFinished.Equal(complDate) {
-		task.yearlyBestStrike[complDate.Year()][complDate.Month()] = task.currentStrike
+	for _, year := range years {
+		for month := time.January; month <= time.December; month++ {
+			all = append(all, task.yearlyTaskCompletion[year][month]...)
+		}
 	}
+
+	return all
 }
 
 // MonthCompletionTime returns task completion at given year and month.
@@ -170,18 +422,23 @@ func (task *Task) WasCompletedAt(year int, month time.Month, day int) bool {
 		return false
 	}
 
-	atDate := time.Date(year, month, day, 0, 0, 0, 0, &time.Location{})
+	atDate := time.Date(year, month, day, 0, 0, 0, 0, task.Now().Location())
 
 	return slices.ContainsFunc(mcmpl, func(t time.Time) bool { return AreSameDates(t, atDate) })
 }
 
 // WasCompletedToday returns whether the Task was completed at current GetTime day.
 func (task *Task) WasCompletedToday() bool {
-	return AreSameDates(task.GetTime(), task.lastTimeCompleted)
+	return AreSameDates(task.Now(), task.lastTimeCompleted)
 }
 
 // CurrentStrike returns how many days in a row were task finished.
+// For quit tasks it is number of clean days since the last relapse.
 func (task *Task) CurrentStrike() int {
+	if task.Kind == TaskKindQuit {
+		return task.cleanDaysStrike()
+	}
+
 	if task.IsStrikeContinued() {
 		return task.currentStrike
 	}
@@ -190,14 +447,68 @@ func (task *Task) CurrentStrike() int {
 }
 
 // IsStrikeContinued returns whether strike was broken
-// meaning there was over 1 day break from finishing it.
+// meaning task Schedule was not kept since last time it was finished.
 func (task *Task) IsStrikeContinued() bool {
-	return AreSameDates(task.GetTime(), task.lastTimeCompleted) ||
-		AreSameDates(task.GetTime().AddDate(0, 0, -1), task.lastTimeCompleted)
+	return task.streakContinues(task.lastTimeCompleted, task.Now())
+}
+
+// streakContinues returns whether a strike which last completion happened
+// at last date is still kept at given date according to the task Schedule.
+func (task *Task) streakContinues(last, date time.Time) bool {
+	if last.IsZero() {
+		return false
+	}
+
+	gap := daysBetween(last, date)
+	if gap < 0 {
+		return false
+	}
+
+	switch task.Schedule.Kind {
+	case ScheduleDaily:
+		return gap <= 1 || gap-task.skippedBetween(last, date) <= 1
+	case ScheduleEveryNDays:
+		interval := max(task.Schedule.Interval, 1)
+
+		return gap <= interval || gap-task.skippedBetween(last, date) <= interval
+	case ScheduleWeekdays:
+		// no scheduled day can be missed between last completion and date
+		for day := last.AddDate(0, 0, 1); daysBetween(day, date) > 0; day = day.AddDate(0, 0, 1) {
+			if task.Schedule.IsDue(day) && !task.IsSkipped(day) {
+				return false
+			}
+		}
+
+		return true
+	case ScheduleTimesPerWeek:
+		// each finished week since last completion must reach its target
+		for week := weekStart(last); daysBetween(week, weekStart(date)) > 0; week = week.AddDate(0, 0, daysInWeek) {
+			weekEnd := week.AddDate(0, 0, daysInWeek-1)
+			y, m, d := weekEnd.Date()
+
+			if task.WeekCompletion(y, m, d) < task.expected(week, weekEnd) {
+				return false
+			}
+		}
+
+		return true
+	case ScheduleTimesPerMonth:
+		// each finished month since last completion must reach its target
+		month := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, last.Location())
+		for ; daysBetween(month.AddDate(0, 1, -1), date) > 0; month = month.AddDate(0, 1, 0) {
+			if task.MonthCompletion(month.Year(), month.Month()) < task.expected(month, month.AddDate(0, 1, -1)) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return gap <= 1
 }
 
 // AreSameDates is a helper function that checks if t1 t2 time.Time
-// have the same day date meaning year, month and day.
+// have the same day date meaning year, month and day in configured location.
 func AreSameDates(one, other time.Time) bool {
 	type date struct {
 		y int
@@ -205,10 +516,21 @@ func AreSameDates(one, other time.Time) bool {
 		d int
 	}
 
-	y, m, d := one.Date()
+	y, m, d := inLocation(one).Date()
 	oneDate := date{y, m, d}
-	y, m, d = other.Date()
+	y, m, d = inLocation(other).Date()
 	otherDate := date{y, m, d}
 
 	return oneDate == otherDate
 }
+
+// idBytes is number of random bytes in task ID.
+const idBytes = 8
+
+// newID returns random task ID.
+func newID() string {
+	id := make([]byte, idBytes)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
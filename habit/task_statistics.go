@@ -1,6 +1,7 @@
 package habit
 
 import (
+	"slices"
 	"time"
 )
 
@@ -15,7 +16,7 @@ type MonthlyTaskCompletion map[time.Month][]time.Time
 type YearlyTaskCompletion map[int]MonthlyTaskCompletion
 
 // MonthlyBestStrike is type for storing task best longest strike
-// that happened over a month.
+// that happened within a month so its values are in range 0-31.
 type MonthlyBestStrike map[time.Month]int
 
 // YearlyBestStrike stores a record of MonthlyBestStrike
@@ -23,7 +24,7 @@ type MonthlyBestStrike map[time.Month]int
 type YearlyBestStrike map[int]MonthlyBestStrike
 
 func (task *Task) CurrentYearCompletion() int {
-	return task.YearCompletion(task.GetTime().Year())
+	return task.YearCompletion(task.Now().Year())
 }
 
 // AllCompletion returns completion for current week, month and year.
@@ -33,6 +34,16 @@ func (task *Task) AllCompletion() (int, int, int) {
 		task.CurrentYearCompletion()
 }
 
+// AllTarget returns number of completions expected by task Schedule
+// over current week, month and year.
+func (task *Task) AllTarget() (int, int, int) {
+	y, m, d := task.Now().Date()
+
+	return task.WeekTarget(y, m, d),
+		task.MonthTarget(y, m),
+		task.YearTarget(y)
+}
+
 // AllStrike returns current, best weekly and best yearly strike information.
 func (task *Task) AllStrike() (int, int, int) {
 	return task.CurrentStrike(),
@@ -66,43 +77,47 @@ func (task *Task) MonthCompletion(year int, month time.Month) int {
 }
 
 func (task *Task) CurrentMonthCompletion() int {
-	y, m, _ := task.GetTime().Date()
+	y, m, _ := task.Now().Date()
 
 	return task.MonthCompletion(y, m)
 }
 
+// CurrentMonthCompletionRate returns part of completions expected this
+// month up to today that were done. Month without expected days has rate 1.
+func (task *Task) CurrentMonthCompletionRate() float64 {
+	now := task.Now()
+	y, m, _ := now.Date()
+
+	expected := task.expected(time.Date(y, m, 1, 0, 0, 0, 0, now.Location()), now)
+	if expected == 0 {
+		return 1
+	}
+
+	return float64(task.MonthCompletion(y, m)) / float64(expected)
+}
+
 // Returns number of completions over the week represented by given date.
 // Week is previous Monday up to given date.
 func (task *Task) WeekCompletion(year int, month time.Month, day int) int {
-	mcp := task.MonthCompletionTime(year, month)
-	if mcp == nil {
-		return 0
-	}
-	// we count all completions that are less than end
-	// a TODO: To be checked if time here and in weekBegin is proper with edge cases.
-	end := time.Date(year, month, day, 23, 59, 59, 59, task.GetTime().Location())
-
-	weekDay := int(end.Weekday())
-	// counting Sunday as seventh day of the week
-	if weekDay == 0 {
-		weekDay = 7
-	}
-
-	weekBegin := time.Date(year, month, day, 0, 0, 0, 0, task.GetTime().Location()).AddDate(0, 0, 1-weekDay)
+	// completions are compared by civil day as completions after midnight
+	// before day start hour are kept at the last moment of previous day
+	date := time.Date(year, month, day, 0, 0, 0, 0, task.Now().Location())
+	weekBegin := weekStart(date)
 
-	// handle overlapping months
-	if weekBegin.Month() != end.Month() {
-		mcp = append(task.MonthCompletionTime(weekBegin.Year(), weekBegin.Month()), mcp...)
+	mcp := task.MonthCompletionTime(year, month)
+	// handle week starting in previous month
+	if weekBegin.Month() != date.Month() {
+		mcp = slices.Concat(task.MonthCompletionTime(weekBegin.Year(), weekBegin.Month()), mcp)
 	}
 
 	counter := 0
 
 	for _, cdt := range mcp {
-		if cdt.Before(weekBegin) {
+		if daysBetween(weekBegin, cdt) < 0 {
 			continue
 		}
 
-		if cdt.After(end) {
+		if daysBetween(cdt, date) < 0 {
 			break
 		}
 
@@ -115,33 +130,55 @@ func (task *Task) WeekCompletion(year int, month time.Month, day int) int {
 // CurrentWeekCompletion returns number of task completions over the whole week up to
 // current day. The week in this sense is treated as last Monday till current day.
 func (task *Task) CurrentWeekCompletion() int {
-	y, m, d := task.GetTime().Date()
+	y, m, d := task.Now().Date()
 
 	return task.WeekCompletion(y, m, d)
 }
 
-func (task *Task) YearBestStrike(year int) int {
-	monthlyStrikes, exist := task.yearlyBestStrike[year]
-	if !exist {
-		return 0
-	}
+// WeekTarget returns number of completions expected by task Schedule over
+// the whole week (Monday - Sunday) that contains given date. Skipped days
+// are not expected so they are left out of all targets.
+func (task *Task) WeekTarget(year int, month time.Month, day int) int {
+	begin := weekStart(time.Date(year, month, day, 0, 0, 0, 0, task.Now().Location()))
+
+	return task.expected(begin, begin.AddDate(0, 0, daysInWeek-1))
+}
 
-	var max int
+// MonthTarget returns number of completions expected by task Schedule over the given month.
+func (task *Task) MonthTarget(year int, month time.Month) int {
+	begin := time.Date(year, month, 1, 0, 0, 0, 0, task.Now().Location())
 
-	for _, strike := range monthlyStrikes {
-		if strike > max {
-			max = strike
-		}
+	return task.expected(begin, begin.AddDate(0, 1, -1))
+}
+
+// YearTarget returns number of completions expected by task Schedule over the given year.
+func (task *Task) YearTarget(year int) int {
+	begin := time.Date(year, time.January, 1, 0, 0, 0, 0, task.Now().Location())
+
+	return task.expected(begin, begin.AddDate(1, 0, -1))
+}
+
+// YearBestStrike returns the longest strike contained within given year.
+func (task *Task) YearBestStrike(year int) int {
+	if task.Kind == TaskKindQuit {
+		return task.longestCleanRun(
+			time.Date(year, time.January, 1, 0, 0, 0, 0, task.Now().Location()),
+			time.Date(year, time.December, 31, 0, 0, 0, 0, task.Now().Location()))
 	}
 
-	return max
+	return task.bestStrikeInYear[year]
 }
 
-// TODO: CurrentMonthBestStrike actually shows best strike ever that happens to also
-// have completions in given month. This probably should be refactored so that month best
-// strike means longest completion over period of ~30 days (given month) and returned values
-// should be in range 0-31.
+// MonthBestStrike returns the longest strike contained within given month.
+// Strike that started in previous month counts only its days in this month
+// so returned value is in range 0-31.
 func (task *Task) MonthBestStrike(year int, month time.Month) int {
+	if task.Kind == TaskKindQuit {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, task.Now().Location())
+
+		return task.longestCleanRun(first, first.AddDate(0, 1, -1))
+	}
+
 	monthlyStrikes, exist := task.yearlyBestStrike[year]
 	if !exist {
 		return 0
@@ -151,22 +188,45 @@ func (task *Task) MonthBestStrike(year int, month time.Month) int {
 }
 
 func (task *Task) CurrentMonthBestStrike() int {
-	y, m, _ := task.GetTime().Date()
+	y, m, _ := task.Now().Date()
 
 	return task.MonthBestStrike(y, m)
 }
 
 func (task *Task) CurrentYearBestStrike() int {
-	return task.YearBestStrike(task.GetTime().Year())
-}
-
-// initializeDateMaps checks if YearlyBestStrike or YearlyTaskHistory
-// are properly initialized and if not does that with given value init function.
-func initializeDateMaps[Y ~map[int]M, M ~map[time.Month]V,
-	V int | []time.Time](
-	yearlyHistory Y, year int,
-	month time.Month, initFunction func() V,
-) {
-	yearlyHistory[year] = make(M, numberOfMonths)
-	yearlyHistory[year][month] = initFunction()
+	return task.YearBestStrike(task.Now().Year())
+}
+
+// CurrentWeekStrike returns current strike counting only days in current ISO week.
+func (task *Task) CurrentWeekStrike() int {
+	return task.periodStrike(task.strikeThisWeek)
+}
+
+// CurrentMonthStrike returns current strike counting only days in current month.
+func (task *Task) CurrentMonthStrike() int {
+	return task.periodStrike(task.strikeThisMonth)
+}
+
+// BestStrike returns the longest strike ever.
+func (task *Task) BestStrike() int {
+	if task.Kind == TaskKindQuit {
+		return task.longestCleanRun(task.CreationDate, task.Now())
+	}
+
+	return task.strikeAllTime.Best
+}
+
+// periodStrike returns current strike limited to the period of strike tracker.
+func (task *Task) periodStrike(strike Strike) int {
+	now := task.Now()
+
+	if task.Kind == TaskKindQuit {
+		return min(task.cleanDaysStrike(), daysBetween(strike.periodStart(now), now)+1)
+	}
+
+	if !strike.samePeriod(now) || !task.IsStrikeContinued() {
+		return 0
+	}
+
+	return strike.Count
 }
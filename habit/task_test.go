@@ -1,6 +1,7 @@
 package habit_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -247,9 +248,8 @@ func TestCompletionChangingMonth(t *testing.T) {
 			t.Fatal(err.Error())
 		}
 
-		// TODO: Probably the monthly will change that it will return c+1 as
-		// number of completions only in April will count.
-		if err := validateStrike(task, c+3, c+3, c+3); err != nil {
+		// only days of the strike in April count to monthly best
+		if err := validateStrike(task, c+3, c+1, c+3); err != nil {
 			t.Fatal(err.Error())
 		}
 
@@ -278,8 +278,9 @@ func TestCompletionChangingYear(t *testing.T) {
 		startDate = startDate.AddDate(0, 0, 1)
 	}
 
-	// 2025-01-05 and not completed yet
-	if err := validateStrike(task, compl, compl, compl); err != nil {
+	// 2025-01-05 and not completed yet, only days in 2025 count to
+	// monthly and yearly best
+	if err := validateStrike(task, compl, 4, 4); err != nil {
 		t.Fatal(err.Error())
 	}
 
@@ -287,3 +288,189 @@ func TestCompletionChangingYear(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 }
+
+func TestBackfillCompletion(t *testing.T) {
+	t.Parallel()
+
+	dit := dayIncreasingTime{time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)}
+	task := habit.WithCustomTime("read a book", "at least one chapter", dit.Now)
+
+	// complete 1-3 and 5-6 of May forgetting about the 4th
+	for day := range 6 {
+		if day != 3 {
+			task.MakeCompleted()
+		}
+
+		dit.AddDay()
+	}
+
+	dit.CurrentTime = dit.CurrentTime.AddDate(0, 0, -1)
+
+	if err := validateStrike(task, 2, 3, 3); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	forgotten := time.Date(2024, time.May, 4, 20, 0, 0, 0, time.UTC)
+	task.MakeCompletedAt(forgotten)
+
+	if !task.WasCompletedAt(2024, time.May, 4) {
+		t.Fatal("Task should be completed at backfilled date")
+	}
+
+	if err := validateStrike(task, 6, 6, 6); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	completions := task.MonthCompletionTime(2024, time.May)
+	for i := 1; i < len(completions); i++ {
+		if !completions[i-1].Before(completions[i]) {
+			t.Fatalf("Completions are not sorted: %v", completions)
+		}
+	}
+
+	// completing future date should not be possible
+	task.MakeCompletedAt(dit.CurrentTime.AddDate(0, 0, 1))
+
+	if compl := task.CurrentMonthCompletion(); compl != 6 {
+		t.Fatalf("Task completion is %d when it should be 6", compl)
+	}
+
+	task.MakeUnCompletedAt(time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC))
+
+	if err := validateStrike(task, 4, 4, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := validateCompletion(task, 1, 5, 5); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// removing today's completion should move last completion to previous day
+	task.MakeUnCompletedAt(dit.CurrentTime)
+
+	if last := task.LastTimeCompleted(); !habit.AreSameDates(last, forgotten.AddDate(0, 0, 1)) {
+		t.Fatalf("Last completion is %v when it should be %v", last, forgotten.AddDate(0, 0, 1))
+	}
+
+	if err := validateStrike(task, 3, 3, 3); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestBackfillPreviousYear(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.January, 2, 9, 0, 0, 0, time.UTC)
+	task := habit.WithCustomTime("stretch", "", func() time.Time { return now })
+
+	task.MakeCompleted()
+	task.MakeCompletedAt(time.Date(2024, time.December, 31, 9, 0, 0, 0, time.UTC))
+	task.MakeCompletedAt(time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC))
+
+	if strike := task.CurrentStrike(); strike != 3 {
+		t.Fatalf("Task strike is %d when it should be 3", strike)
+	}
+
+	if best := task.YearBestStrike(2024); best != 1 {
+		t.Fatalf("Task best strike in 2024 is %d when it should be 1", best)
+	}
+
+	if compl := task.YearCompletion(2024); compl != 1 {
+		t.Fatalf("Task completion in 2024 is %d when it should be 1", compl)
+	}
+}
+
+func TestBestStrikeWithinMonth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		first     time.Time
+		days      int
+		month     int
+		year      int
+		prevMonth int
+	}{
+		{
+			name:      "within single month",
+			first:     time.Date(2024, time.May, 10, 12, 0, 0, 0, time.UTC),
+			days:      5,
+			month:     5,
+			year:      5,
+			prevMonth: 0,
+		},
+		{
+			name:      "across month boundary",
+			first:     time.Date(2024, time.April, 25, 12, 0, 0, 0, time.UTC),
+			days:      10,
+			month:     4,
+			year:      10,
+			prevMonth: 6,
+		},
+		{
+			name:      "whole month and more",
+			first:     time.Date(2024, time.January, 20, 12, 0, 0, 0, time.UTC),
+			days:      45,
+			month:     4,
+			year:      45,
+			prevMonth: 29,
+		},
+		{
+			name:      "across year boundary",
+			first:     time.Date(2023, time.December, 20, 12, 0, 0, 0, time.UTC),
+			days:      20,
+			month:     8,
+			year:      8,
+			prevMonth: 12,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// day after the strike that is not completed yet
+			now := test.first.AddDate(0, 0, test.days)
+			task := habit.WithCustomTime("read", "", func() time.Time { return now })
+
+			for day := range test.days {
+				task.MakeCompletedAt(test.first.AddDate(0, 0, day))
+			}
+
+			if err := validateStrike(task, test.days, test.month, test.year); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			prev := now.AddDate(0, -1, 0)
+			if best := task.MonthBestStrike(prev.Year(), prev.Month()); best != test.prevMonth {
+				t.Fatalf("Previous month best strike is %d when it should be %d", best, test.prevMonth)
+			}
+		})
+	}
+}
+
+func TestFindTask(t *testing.T) {
+	t.Parallel()
+
+	tasks := habit.TaskList{habit.NewTask("Read book", ""), habit.NewTask("Run", ""), habit.NewTask("Running shoes", "")}
+
+	tests := []struct {
+		query string
+		index int
+		err   error
+	}{
+		{query: tasks[2].ID, index: 2},
+		{query: "run", index: 1},
+		{query: "read", index: 0},
+		{query: "runn", index: 2},
+		{query: "r", index: -1, err: habit.ErrAmbiguousTask},
+		{query: "walk", index: -1, err: habit.ErrTaskNotFound},
+	}
+
+	for _, test := range tests {
+		idx, err := tasks.Find(test.query)
+		if idx != test.index || !errors.Is(err, test.err) {
+			t.Fatalf("Find(%q) returned %d, %v expected %d, %v", test.query, idx, err, test.index, test.err)
+		}
+	}
+}
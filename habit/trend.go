@@ -0,0 +1,156 @@
+package habit
+
+import (
+	"time"
+)
+
+// PeriodRate is task completion over a week or month. For quit tasks
+// Completed counts clean days and Expected days that could be clean.
+type PeriodRate struct {
+	Start     time.Time
+	Completed int
+	Expected  int
+}
+
+// Rate returns part of expected completions that were done.
+// Period without expected completions has rate 0.
+func (rate PeriodRate) Rate() float64 {
+	if rate.Expected == 0 {
+		return 0
+	}
+
+	return float64(rate.Completed) / float64(rate.Expected)
+}
+
+// StrikeRun is a finished or ongoing strike. For quit tasks it is
+// a run of clean days between relapses.
+type StrikeRun struct {
+	Start  time.Time
+	End    time.Time
+	Length int
+}
+
+// WeeklyRates returns completion of the last weeks ending with current
+// week ordered from the oldest. Only days up to today and not before
+// task creation are taken into account.
+func (task *Task) WeeklyRates(weeks int) []PeriodRate {
+	current := weekStart(dayOf(task.Now()))
+	rates := make([]PeriodRate, 0, weeks)
+
+	for week := weeks - 1; week >= 0; week-- {
+		start := current.AddDate(0, 0, -week*daysInWeek)
+		rates = append(rates, task.periodRate(start, start.AddDate(0, 0, daysInWeek-1)))
+	}
+
+	return rates
+}
+
+// MonthlyRates returns completion of the last months ending with current
+// month ordered from the oldest. Only days up to today and not before
+// task creation are taken into account.
+func (task *Task) MonthlyRates(months int) []PeriodRate {
+	now := task.Now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	rates := make([]PeriodRate, 0, months)
+
+	for month := months - 1; month >= 0; month-- {
+		start := current.AddDate(0, -month, 0)
+		rates = append(rates, task.periodRate(start, start.AddDate(0, 1, -1)))
+	}
+
+	return rates
+}
+
+// periodRate returns completion of days from first to last date inclusive.
+func (task *Task) periodRate(first, last time.Time) PeriodRate {
+	rate := PeriodRate{Start: first}
+
+	if created := dayOf(task.CreationDate); first.Before(created) {
+		first = created
+	}
+
+	if now := dayOf(task.Now()); last.After(now) {
+		last = now
+	}
+
+	if daysBetween(first, last) < 0 {
+		return rate
+	}
+
+	if task.Kind == TaskKindQuit {
+		for day := first; daysBetween(day, last) >= 0; day = day.AddDate(0, 0, 1) {
+			rate.Expected++
+
+			if task.IsClean(day) {
+				rate.Completed++
+			}
+		}
+
+		return rate
+	}
+
+	for day := first; daysBetween(day, last) >= 0; day = day.AddDate(0, 0, 1) {
+		if task.WasCompletedAt(day.Date()) {
+			rate.Completed++
+		}
+	}
+
+	rate.Expected = task.expected(first, last)
+
+	return rate
+}
+
+// WeekdayCompletions returns number of completions (relapses for quit
+// tasks) done on each day of week starting with Monday.
+func (task *Task) WeekdayCompletions() [daysInWeek]int {
+	counts := [daysInWeek]int{}
+
+	for _, completion := range task.completions() {
+		counts[(int(completion.Weekday())+daysInWeek-1)%daysInWeek]++
+	}
+
+	return counts
+}
+
+// StrikeRuns returns all strikes ordered from the oldest with the
+// last one ongoing if it is still continued.
+func (task *Task) StrikeRuns() []StrikeRun {
+	runs := []StrikeRun{}
+
+	if task.Kind == TaskKindQuit {
+		for day := dayOf(task.CreationDate); daysBetween(day, task.Now()) >= 0; day = day.AddDate(0, 0, 1) {
+			switch {
+			case !task.IsClean(day):
+			case len(runs) > 0 && daysBetween(runs[len(runs)-1].End, day) == 1:
+				runs[len(runs)-1].End = day
+				runs[len(runs)-1].Length++
+			default:
+				runs = append(runs, StrikeRun{Start: day, End: day, Length: 1})
+			}
+		}
+
+		return runs
+	}
+
+	last := time.Time{}
+
+	for _, completion := range task.completions() {
+		if task.streakContinues(last, completion) && len(runs) > 0 {
+			runs[len(runs)-1].End = completion
+			runs[len(runs)-1].Length++
+		} else {
+			runs = append(runs, StrikeRun{Start: completion, End: completion, Length: 1})
+		}
+
+		last = completion
+	}
+
+	return runs
+}
+
+// dayOf returns midnight of date day in configured location.
+func dayOf(date time.Time) time.Time {
+	y, m, d := inLocation(date).Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, inLocation(date).Location())
+}
@@ -0,0 +1,88 @@
+package habit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+func TestPeriodRates(t *testing.T) {
+	t.Parallel()
+
+	// Monday
+	timer := &dayIncreasingTime{time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC)}
+	task := habit.NewTask("rates", "")
+	task.GetTime = timer.Now
+	task.CreationDate = timer.Now()
+
+	// whole first week and Monday and Tuesday of the second one
+	for range 9 {
+		task.MakeCompleted()
+		timer.AddDay()
+	}
+
+	timer.AddDay()
+
+	weeks := task.WeeklyRates(3)
+	if len(weeks) != 3 {
+		t.Fatalf("Expected 3 weekly rates got %d", len(weeks))
+	}
+
+	if weeks[0].Expected != 0 || weeks[0].Rate() != 0 {
+		t.Fatalf("Week before creation should have nothing expected got %+v", weeks[0])
+	}
+
+	if weeks[1].Completed != 7 || weeks[1].Rate() != 1 {
+		t.Fatalf("First week should be fully completed got %+v", weeks[1])
+	}
+
+	if weeks[2].Completed != 2 || weeks[2].Expected != 4 {
+		t.Fatalf("Current week should have 2 of 4 days completed got %+v", weeks[2])
+	}
+
+	months := task.MonthlyRates(2)
+	if months[0].Expected != 0 || months[1].Completed != 9 || months[1].Expected != 11 {
+		t.Fatalf("Unexpected monthly rates %+v", months)
+	}
+}
+
+func TestWeekdayCompletionsAndStrikeRuns(t *testing.T) {
+	t.Parallel()
+
+	// Monday
+	timer := &dayIncreasingTime{time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC)}
+	task := habit.NewTask("runs", "")
+	task.GetTime = timer.Now
+	task.CreationDate = timer.Now()
+
+	for day := range 10 {
+		if day != 3 && day != 4 {
+			task.MakeCompleted()
+		}
+
+		timer.AddDay()
+	}
+
+	if counts := task.WeekdayCompletions(); counts != [7]int{2, 2, 2, 0, 0, 1, 1} {
+		t.Fatalf("Unexpected weekday completions %v", counts)
+	}
+
+	runs := task.StrikeRuns()
+	if len(runs) != 2 || runs[0].Length != 3 || runs[1].Length != 5 {
+		t.Fatalf("Expected strikes of 3 and 5 days got %+v", runs)
+	}
+
+	if !habit.AreSameDates(runs[1].Start, time.Date(2024, time.May, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Second strike should start on 11 May got %v", runs[1].Start)
+	}
+
+	// the same history read as relapses
+	task.Kind = habit.TaskKindQuit
+	task.RecomputeStats()
+
+	runs = task.StrikeRuns()
+	if len(runs) != 2 || runs[0].Length != 2 || runs[1].Length != 1 {
+		t.Fatalf("Expected clean runs of 2 and 1 days got %+v", runs)
+	}
+}
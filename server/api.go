@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/bazko1/habitui/habit"
@@ -53,6 +54,9 @@ func createHandler(controller Controller) http.Handler {
 	handler.HandleFunc("POST /user/login", handlePostUserLogin(controller))
 	handler.HandleFunc("GET /user/habits", handleGetUserHabits(controller))
 	handler.HandleFunc("PUT /user/habits", handlePutUserHabits(controller))
+	handler.HandleFunc("GET /user/habits/{id}", handleGetUserHabit(controller))
+	handler.HandleFunc("PUT /user/habits/{id}", handlePutUserHabit(controller))
+	handler.HandleFunc("DELETE /user/habits/{id}", handleDeleteUserHabit(controller))
 
 	return logRequestMiddleware(handler)
 }
@@ -158,7 +162,16 @@ func handlePutUserHabits(controller Controller) http.HandlerFunc {
 		decoder := json.NewDecoder(r.Body)
 		if err := decoder.Decode(&newHabits); err != nil {
 			log.Printf("Error decoding TaskList from body: %v", err)
+
+			if errors.Is(err, habit.ErrDuplicateID) {
+				http.Error(w, fmt.Sprintf("Incorrect input error: %v", err), http.StatusUnprocessableEntity)
+
+				return
+			}
+
 			http.Error(w, "Error decoding task list data.", http.StatusInternalServerError)
+
+			return
 		}
 
 		username, ok := claims["username"].(string)
@@ -235,3 +248,147 @@ func handlePostUserLogin(controller Controller) http.HandlerFunc {
 		_, _ = w.Write(bytes)
 	}
 }
+
+// authorizedUserHabits returns user identified by request bearer token together
+// with user habits. On failure error response is written and false is returned.
+func authorizedUserHabits(controller Controller, w http.ResponseWriter, r *http.Request,
+) (UserModel, habit.TaskList, bool) {
+	claims, err := getBearerToken(r)
+	if err != nil {
+		log.Printf("Err when getting bearer token: %v", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+		return UserModel{}, nil, false
+	}
+
+	username, ok := claims["username"].(string)
+	if !ok {
+		log.Printf("Failed to cast %#v to string.", claims["username"])
+		http.Error(w, "Failed to get user.", http.StatusInternalServerError)
+
+		return UserModel{}, nil, false
+	}
+
+	user, err := controller.GetUserByName(username)
+	if errors.Is(err, ErrUsernameDoesNotExist) {
+		log.Printf("User %s does not exists", username)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+		return UserModel{}, nil, false
+	}
+
+	if err != nil {
+		log.Printf("Getting user by name error %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+
+		return UserModel{}, nil, false
+	}
+
+	habits, err := controller.GetUserHabits(user)
+	if err != nil {
+		log.Printf("Getting user habits error: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+
+		return UserModel{}, nil, false
+	}
+
+	return user, habits, true
+}
+
+func handleGetUserHabit(controller Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, habits, ok := authorizedUserHabits(controller, w, r)
+		if !ok {
+			return
+		}
+
+		idx := habits.IndexByID(r.PathValue("id"))
+		if idx < 0 {
+			http.Error(w, "Habit not found.", http.StatusNotFound)
+
+			return
+		}
+
+		bytes, err := json.Marshal(habits[idx])
+		if err != nil {
+			log.Printf("error when marshaling habit: %v", err)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bytes)
+	}
+}
+
+// handlePutUserHabit replaces habit with ID given in path
+// or adds it to user habits if there is no such habit yet.
+func handlePutUserHabit(controller Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, habits, ok := authorizedUserHabits(controller, w, r)
+		if !ok {
+			return
+		}
+
+		newHabit := habit.Task{}
+		if err := json.NewDecoder(r.Body).Decode(&newHabit); err != nil {
+			log.Printf("Error decoding Task from body: %v", err)
+			http.Error(w, "Error decoding task data.", http.StatusInternalServerError)
+
+			return
+		}
+
+		if id := r.PathValue("id"); newHabit.ID != id {
+			http.Error(w, fmt.Sprintf("Incorrect input error: habit id %q differs from %q", newHabit.ID, id),
+				http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		status := http.StatusOK
+		if idx := habits.IndexByID(newHabit.ID); idx >= 0 {
+			habits[idx] = newHabit
+		} else {
+			habits = append(habits, newHabit)
+			status = http.StatusCreated
+		}
+
+		// keep manual order when position of the habit changed
+		habits.SortByPosition()
+
+		if err := controller.UpdateUserHabits(user, habits); err != nil {
+			log.Printf("Updating user habits error: %v", err)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(status)
+	}
+}
+
+func handleDeleteUserHabit(controller Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, habits, ok := authorizedUserHabits(controller, w, r)
+		if !ok {
+			return
+		}
+
+		idx := habits.IndexByID(r.PathValue("id"))
+		if idx < 0 {
+			http.Error(w, "Habit not found.", http.StatusNotFound)
+
+			return
+		}
+
+		if err := controller.UpdateUserHabits(user, slices.Delete(habits, idx, idx+1)); err != nil {
+			log.Printf("Updating user habits error: %v", err)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bazko1/habitui/client"
 	"github.com/bazko1/habitui/habit"
 	"github.com/bazko1/habitui/server"
 )
@@ -272,3 +273,181 @@ func TestUpdateUserTasks(t *testing.T) {
 		})
 	}
 }
+
+func doRequest(t *testing.T, method, url, token, body string) (int, []byte) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error during %s call %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	bytes, _ := io.ReadAll(resp.Body)
+
+	return resp.StatusCode, bytes
+}
+
+func TestUserHabitByID(t *testing.T) {
+	for _, cntrl := range controllerTypes {
+		t.Run(cntrl, func(t *testing.T) {
+			t.Parallel()
+			ln := startServer(t, cntrl)
+
+			defer ln.Close()
+			address := "http://" + ln.Addr().String()
+
+			createUser(t, address)
+			token, _ := loginUser(t, address)["access_token"].(string)
+
+			task := habit.NewTask("work on habitui", "daily app grind")
+			task.MakeCompleted()
+			url := address + "/user/habits/" + task.ID
+			b, _ := json.Marshal(task)
+
+			if code, _ := doRequest(t, http.MethodPut, url, token, string(b)); code != http.StatusCreated {
+				t.Fatalf("Put new habit should return %d while it returned %d", http.StatusCreated, code)
+			}
+
+			task.Name = "work on habitui server"
+			b, _ = json.Marshal(task)
+
+			if code, _ := doRequest(t, http.MethodPut, url, token, string(b)); code != http.StatusOK {
+				t.Fatalf("Put existing habit should return %d while it returned %d", http.StatusOK, code)
+			}
+
+			if code, _ := doRequest(t, http.MethodPut, address+"/user/habits/other", token, string(b)); code !=
+				http.StatusUnprocessableEntity {
+				t.Fatalf("Put habit with other id should return %d while it returned %d", http.StatusUnprocessableEntity, code)
+			}
+
+			code, body := doRequest(t, http.MethodGet, url, token, "")
+			if code != http.StatusOK {
+				t.Fatalf("Get habit should return %d while it returned %d", http.StatusOK, code)
+			}
+
+			stored := habit.Task{}
+			if err := json.Unmarshal(body, &stored); err != nil || stored.Name != task.Name || !stored.WasCompletedToday() {
+				t.Fatalf("Stored habit %s differs from %s: %v", body, b, err)
+			}
+
+			duplicated, _ := json.Marshal(habit.TaskList{task, task})
+			if code, _ := doRequest(t, http.MethodPut, address+"/user/habits", token, string(duplicated)); code !=
+				http.StatusUnprocessableEntity {
+				t.Fatalf("Put habits with duplicated id should return %d while it returned %d",
+					http.StatusUnprocessableEntity, code)
+			}
+
+			if code, _ := doRequest(t, http.MethodDelete, url, token, ""); code != http.StatusNoContent {
+				t.Fatalf("Delete habit should return %d while it returned %d", http.StatusNoContent, code)
+			}
+
+			if code, _ := doRequest(t, http.MethodGet, url, token, ""); code != http.StatusNotFound {
+				t.Fatalf("Get deleted habit should return %d while it returned %d", http.StatusNotFound, code)
+			}
+		})
+	}
+}
+
+func TestClientUserHabit(t *testing.T) {
+	for _, cntrl := range controllerTypes {
+		t.Run(cntrl, func(t *testing.T) {
+			t.Parallel()
+			ln := startServer(t, cntrl)
+
+			defer ln.Close()
+
+			remote := client.HTTPClient{Address: "http://" + ln.Addr().String(), Username: "foo", Password: "test"}
+			if _, err := remote.LoadTasksOrCreateUser(); err != nil {
+				t.Fatalf("Failed to create user: %v", err)
+			}
+
+			task := habit.NewTask("work on habitui", "daily app grind")
+			if err := remote.SaveUserTask(task); err != nil {
+				t.Fatalf("Failed to save new habit: %v", err)
+			}
+
+			task.Name = "work on habitui client"
+			task.MakeCompleted()
+
+			if err := remote.SaveUserTask(task); err != nil {
+				t.Fatalf("Failed to save existing habit: %v", err)
+			}
+
+			stored, err := remote.LoadUserTask(task.ID)
+			if err != nil || stored.Name != task.Name || !stored.WasCompletedToday() {
+				t.Fatalf("Loaded habit %#v differs from saved one: %v", stored, err)
+			}
+
+			if err := remote.DeleteUserTask(task.ID); err != nil {
+				t.Fatalf("Failed to delete habit: %v", err)
+			}
+
+			if _, err := remote.LoadUserTask(task.ID); err == nil {
+				t.Fatalf("Deleted habit should not be loaded")
+			}
+
+			if err := remote.DeleteUserTask(task.ID); err == nil {
+				t.Fatalf("Deleting missing habit should fail")
+			}
+		})
+	}
+}
+
+func TestUserHabitsOrder(t *testing.T) {
+	for _, cntrl := range controllerTypes {
+		t.Run(cntrl, func(t *testing.T) {
+			t.Parallel()
+			ln := startServer(t, cntrl)
+
+			defer ln.Close()
+			address := "http://" + ln.Addr().String()
+
+			createUser(t, address)
+			token, _ := loginUser(t, address)["access_token"].(string)
+
+			first, second, third := habit.NewTask("first", ""), habit.NewTask("second", ""), habit.NewTask("third", "")
+			first.Position, second.Position = 2, 1
+			b, _ := json.Marshal(habit.TaskList{first, second})
+
+			if code, _ := doRequest(t, http.MethodPut, address+"/user/habits", token, string(b)); code != http.StatusOK {
+				t.Fatalf("Put habits should return %d while it returned %d", http.StatusOK, code)
+			}
+
+			b, _ = json.Marshal(third)
+			if code, _ := doRequest(t, http.MethodPut, address+"/user/habits/"+third.ID, token, string(b)); code !=
+				http.StatusCreated {
+				t.Fatalf("Put new habit should return %d while it returned %d", http.StatusCreated, code)
+			}
+
+			first.Position = 3
+			third.Position = 2
+			b, _ = json.Marshal(first)
+			doRequest(t, http.MethodPut, address+"/user/habits/"+first.ID, token, string(b))
+			b, _ = json.Marshal(third)
+			doRequest(t, http.MethodPut, address+"/user/habits/"+third.ID, token, string(b))
+
+			_, body := doRequest(t, http.MethodGet, address+"/user/habits", token, "")
+			habits := habit.TaskList{}
+
+			if err := json.Unmarshal(body, &habits); err != nil {
+				t.Fatalf("Error decoding habits: %v", err)
+			}
+
+			names := []string{}
+			for _, h := range habits {
+				names = append(names, h.Name)
+			}
+
+			if order := strings.Join(names, ","); order != "second,third,first" {
+				t.Fatalf("Habits should be returned in manual order second,third,first got %s", order)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/bazko1/habitui/habit"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var confirmMap = struct { //nolint:gochecknoglobals
+	Yes key.Binding
+}{
+	key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm")),
+}
+
+// hasSelection returns whether cursor is on a task shown in the list.
+func (model Model) hasSelection() bool {
+	return model.cursorRow < len(model.tasks) && !model.tasks[model.cursorRow].Archived
+}
+
+// archiveRows returns archived tasks lines of archive browser.
+func (model Model) archiveRows() []listRow {
+	rows := []listRow{}
+
+	for idx, task := range model.tasks {
+		if task.Archived {
+			rows = append(rows, listRow{task: idx})
+		}
+	}
+
+	return rows
+}
+
+// archiveSelected archives task at cursor and moves cursor to neighbouring task.
+func (model Model) archiveSelected() Model {
+	archived := model.cursorRow
+	if model.cursorRow = model.nextStop(1); model.cursorRow == archived {
+		model.cursorRow = model.nextStop(-1)
+	}
+
+	model.change(archived, func(task *habit.Task) { task.Archived = true })
+
+	return model.refreshList()
+}
+
+// askDelete asks for confirmation before task at row is deleted with its history.
+func (model Model) askDelete(row int) Model {
+	model.deleting = model.tasks[row].ID
+	model.message = fmt.Sprintf("Delete %q with its whole history? y/n", model.tasks[row].Name)
+
+	return model
+}
+
+// updateDeleteConfirmation deletes task waiting for confirmation if key confirms it.
+func (model Model) updateDeleteConfirmation(msg tea.KeyMsg) Model {
+	row := model.tasks.IndexByID(model.deleting)
+	model.deleting = ""
+
+	if row < 0 || !key.Matches(msg, confirmMap.Yes) {
+		return model
+	}
+
+	del := taskDelete{task: model.tasks[row].Clone(), index: row}
+	delete(model.selected, del.task.ID)
+	model.tasks = del.apply(model.tasks)
+	model.history.push(del)
+
+	if model.cursorRow > row || model.cursorRow == len(model.tasks) {
+		model.cursorRow = max(0, model.cursorRow-1)
+	}
+
+	return model.refreshList()
+}
+
+// updateArchiveMode handles keys of archive browser in which archived
+// tasks can be restored or deleted.
+func (model Model) updateArchiveMode(msg tea.KeyMsg) Model {
+	rows := model.archiveRows()
+
+	switch {
+	case key.Matches(msg, model.keys.Quit), key.Matches(msg, model.keys.Archives):
+		model.archiveMode = false
+	case key.Matches(msg, model.keys.Up):
+		model.archiveCursor--
+	case key.Matches(msg, model.keys.Down):
+		model.archiveCursor++
+	case key.Matches(msg, model.keys.Select), key.Matches(msg, model.keys.Archive):
+		if len(rows) == 0 {
+			break
+		}
+
+		model.change(rows[model.archiveCursor].task, func(task *habit.Task) { task.Archived = false })
+		model.cursorRow = rows[model.archiveCursor].task
+		model = model.refreshList()
+	case key.Matches(msg, model.keys.Delete):
+		if len(rows) == 0 {
+			break
+		}
+
+		model = model.askDelete(rows[model.archiveCursor].task)
+	case key.Matches(msg, model.keys.Help):
+		model.help.ShowAll = !model.help.ShowAll
+	}
+
+	model.archiveCursor = max(0, min(model.archiveCursor, len(model.archiveRows())-1))
+
+	return model
+}
+
+// archivedDescription returns description of archived task.
+func archivedDescription(task habit.Task) string {
+	return task.Description + "\n\nArchived, Enter restores and d deletes it."
+}
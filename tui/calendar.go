@@ -1,8 +1,8 @@
 package tui
 
 import (
+	"fmt"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,98 +13,210 @@ import (
 )
 
 const (
-	weekDays       = 7
-	calendarRows   = 5
-	calendarCols   = weekDays
-	calendarFields = calendarRows * calendarCols
+	weekDays = 7
+	// yearWeeks is number of weeks shown in year view.
+	yearWeeks = 53
+	// yearLabelWidth is width of week day names column of year view.
+	yearLabelWidth = 4
 )
 
-// RenderCalendar creates calendar string based on task that
-// shows days in a month when task was completed.
-func RenderCalendar(task habit.Task) string { //nolint:funlen // lets keep it as long blob for now
-	now := task.GetTime()
-	todayRowCol := [2]int{}
-	monthDays := getDaysInMonth(time.Now())
-	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-	firstDayWeekday := int(firstDay.Weekday())
-
-	// count Monday as first day instead of Sunday
-	if firstDayWeekday == 0 {
-		firstDayWeekday = 7
-	}
-	// Mon is 1, Tue 2, ..., Sun 7 if we have Monday
-	// no days are filled before 1st day of month.
-	firstDayWeekday--
-
-	daysSlice := make([]string, 0, calendarFields)
-
-	completedDays := [][2]int{}
-
-	for i := firstDayWeekday; i > 0; i-- {
-		prvMonthDay := firstDay.AddDate(0, 0, -i)
-		daysSlice = append(daysSlice, strconv.Itoa(prvMonthDay.Day()))
+// calendarStyles holds styles of calendar days for task kind.
+type calendarStyles struct {
+	base     lipgloss.Style
+	label    lipgloss.Style
+	finished lipgloss.Style
+	partial  lipgloss.Style
+	skipped  lipgloss.Style
+}
 
-		if task.WasCompletedAt(prvMonthDay.Year(), prvMonthDay.Month(), prvMonthDay.Day()) {
-			completedDays = append(completedDays, [2]int{0, firstDayWeekday - i})
-		}
+func newCalendarStyles(task habit.Task) calendarStyles {
+	re := lipgloss.NewRenderer(os.Stdout)
+	base := re.NewStyle().Padding(0, 1)
+	styles := calendarStyles{
+		base:     base,
+		label:    re.NewStyle().Foreground(lipgloss.Color("241")),
+		finished: base.Copy().Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F")),
+		partial:  base.Copy().Foreground(lipgloss.Color("#E0C050")).Background(lipgloss.Color("#4A3F10")),
+		skipped:  base.Copy().Foreground(lipgloss.Color("#8AA4C8")).Background(lipgloss.Color("#26334A")),
 	}
 
-	for i := 1; i <= monthDays; i++ {
-		daysSlice = append(daysSlice, strconv.Itoa(i))
-		calendarDay := firstDayWeekday + i
-		row := (calendarDay - 1) / weekDays
-		col := (calendarDay - 1) % weekDays
+	if task.Kind == habit.TaskKindQuit {
+		styles.finished = base.Copy().Foreground(lipgloss.Color("#FF6B6B")).Background(lipgloss.Color("#5C1010"))
+	}
 
-		if i == now.Day() {
-			todayRowCol = [2]int{row, col}
-		}
+	return styles
+}
 
-		if task.WasCompletedAt(now.Year(), now.Month(), i) {
-			completedDays = append(completedDays, [2]int{row, col})
-		}
+// day returns style of day depending on whether task was completed,
+// skipped or partially done at that day.
+func (styles calendarStyles) day(task habit.Task, day time.Time) lipgloss.Style {
+	switch {
+	case task.WasCompletedAt(day.Date()):
+		return styles.finished
+	case task.IsSkipped(day):
+		return styles.skipped
+	case task.AmountAt(day) > 0:
+		return styles.partial
 	}
 
-	if leftDays := calendarFields - len(daysSlice); leftDays > 0 {
-		for i := range leftDays {
-			daysSlice = append(daysSlice, strconv.Itoa(i+1))
-		}
-	}
+	return styles.base
+}
 
-	weeks := [][]string{}
+// RenderCalendar creates calendar string based on task that
+// shows days in a month when task was completed.
+func RenderCalendar(task habit.Task) string {
+	return renderCalendar(task, task.Now(), time.Time{})
+}
 
-	var i int
-	for ; i < monthDays/weekDays; i++ {
-		weeks = append(weeks, daysSlice[i*weekDays:i*weekDays+weekDays])
+// renderCalendar renders calendar of month containing given day with
+// highlighted cursor day if cursor is not zero time.
+func renderCalendar(task habit.Task, month, cursor time.Time) string {
+	today := task.Now()
+	first := firstOfMonth(month)
+	// count Monday as first day instead of Sunday, days of
+	// previous month fill the week before 1st day of month.
+	start := first.AddDate(0, 0, -weekdayIndex(first))
+	rows := (weekdayIndex(first) + getDaysInMonth(first) + weekDays - 1) / weekDays
+
+	grid := make([][]time.Time, rows)
+	weeks := make([][]string, rows)
+
+	for row := range rows {
+		for col := range weekDays {
+			day := start.AddDate(0, 0, row*weekDays+col)
+			grid[row] = append(grid[row], day)
+			weeks[row] = append(weeks[row], strconv.Itoa(day.Day()))
+		}
 	}
 
-	weeks = append(weeks, daysSlice[i*weekDays:])
-
-	re := lipgloss.NewRenderer(os.Stdout)
-	baseStyle := re.NewStyle().Padding(0, 1)
-	labelStyle := re.NewStyle().Foreground(lipgloss.Color("241"))
-	finishedStyle := baseStyle.Copy().Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F"))
+	styles := newCalendarStyles(task)
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderRow(true).
 		BorderColumn(true).
+		Headers("Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun").
 		Rows(weeks...).
 		StyleFunc(func(row, col int) lipgloss.Style {
-			style := baseStyle
+			if row == table.HeaderRow {
+				return styles.label.Padding(0, 1)
+			}
+
+			day := grid[row][col]
+			style := styles.day(task, day)
 
-			if slices.ContainsFunc(completedDays, func(a [2]int) bool { return a[0] == row-1 && a[1] == col }) {
-				style = finishedStyle
+			if day.Month() != first.Month() {
+				style = style.Copy().Faint(true)
 			}
 
-			if row-1 == todayRowCol[0] &&
-				col == todayRowCol[1] {
+			if _, ok := task.NoteAt(day); ok {
+				style = style.Copy().Underline(true)
+			}
+
+			if habit.AreSameDates(day, today) {
 				style = style.Copy().Foreground(lipgloss.Color("40"))
 			}
 
+			if !cursor.IsZero() && habit.AreSameDates(day, cursor) {
+				style = style.Copy().Reverse(true).Bold(true)
+			}
+
 			return style
 		})
 
-	dayNames := labelStyle.Render(strings.Join([]string{" Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}, "  "))
-	calendar := lipgloss.JoinVertical(lipgloss.Left, dayNames, t.Render()) + "\n"
+	caption := styles.label.Render(fmt.Sprintf(" %s %d", first.Month(), first.Year()))
+
+	return lipgloss.JoinVertical(lipgloss.Left, t.Render(), caption)
+}
+
+// renderYear renders heatmap of task completions in 53 weeks ending with
+// the week of end day. Columns are weeks and rows are days from Monday.
+// Days after end are left empty.
+func renderYear(task habit.Task, end, cursor time.Time) string {
+	today := task.Now()
+	start := yearStart(end)
+	styles := newCalendarStyles(task)
+	months := []rune(strings.Repeat(" ", yearLabelWidth+yearWeeks))
+
+	// month name is put over week its 1st day falls into
+	for week := range yearWeeks {
+		monday := start.AddDate(0, 0, week*weekDays)
+		if sunday := monday.AddDate(0, 0, weekDays-1); sunday.Day() <= weekDays {
+			name := sunday.Month().String()[:3]
+			if pos := yearLabelWidth + week; pos+len(name) <= len(months) && months[pos-1] == ' ' {
+				copy(months[pos:], []rune(name))
+			}
+		}
+	}
+
+	lines := []string{styles.label.Render(string(months))}
+	dayNames := [weekDays]string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+
+	for weekday := range weekDays {
+		line := styles.label.Render(fmt.Sprintf("%-*s", yearLabelWidth, dayNames[weekday]))
+
+		for week := range yearWeeks {
+			day := start.AddDate(0, 0, week*weekDays+weekday)
+			if day.After(end) {
+				line += " "
+
+				continue
+			}
+
+			style := styles.day(task, day).UnsetPadding().UnsetBackground()
+			if style.GetForeground() == (lipgloss.NoColor{}) {
+				style = style.Foreground(lipgloss.Color("238"))
+			}
+
+			if habit.AreSameDates(day, today) {
+				style = style.Bold(true)
+			}
+
+			if !cursor.IsZero() && habit.AreSameDates(day, cursor) {
+				style = style.Reverse(true)
+			}
+
+			line += style.Render("■")
+		}
+
+		lines = append(lines, line)
+	}
+
+	caption := styles.label.Render(fmt.Sprintf(" %s %d - %s %d",
+		start.Month(), start.Year(), end.Month(), end.Year()))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Render(strings.Join(lines, "\n")),
+		caption)
+}
+
+// yearStart returns Monday that year view ending at end day starts with.
+func yearStart(end time.Time) time.Time {
+	monday := dateOf(end).AddDate(0, 0, -weekdayIndex(end))
+
+	return monday.AddDate(0, 0, -(yearWeeks-1)*weekDays)
+}
+
+// weekdayIndex returns number of days since Monday.
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + weekDays - 1) % weekDays
+}
+
+// dateOf returns midnight of t day.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// lastOfMonth returns midnight of the last day of t month.
+func lastOfMonth(t time.Time) time.Time {
+	return firstOfMonth(t).AddDate(0, 1, -1)
+}
 
-	return calendar
+func getDaysInMonth(t time.Time) int {
+	return lastOfMonth(t).Day()
 }
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clockInterval is how often running TUI checks whether day has changed.
+const clockInterval = time.Minute
+
+// tickMsg is sent every clockInterval aligned to the system clock.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Every(clockInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// updateTick refreshes state that depends on current day once the
+// day has changed e.g. at midnight when TUI was left open.
+func (model Model) updateTick() (Model, tea.Cmd) {
+	today := dateOf(habit.Now())
+	if today.Equal(model.today) {
+		// saving that failed is retried
+		if model.autosave && model.saveState == saveStateFailed {
+			saved, cmd := model.save()
+
+			return saved, tea.Batch(tick(), cmd)
+		}
+
+		return model, tick()
+	}
+
+	model.today = today
+
+	for row := range model.tasks {
+		model.refreshSelected(row)
+	}
+
+	if model.calendarMode && model.hasSelection() {
+		model.calendarCursor = model.clampCursor(model.tasks[model.cursorRow], model.calendarCursor)
+	}
+
+	if !model.autosave {
+		return model, tick()
+	}
+
+	model, cmd := model.save()
+
+	return model, tea.Batch(tick(), cmd)
+}
+
+// clock returns current date and time shown above TUI boxes.
+func clock() string {
+	today := habit.Now()
+
+	return today.Format("2 January 2006") + " " + time.Now().In(habit.Location()).Format("15:04")
+}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+// otherCategory is header name of tasks without category.
+const otherCategory = "other"
+
+// listRow is a line of habits list that is either category header or task.
+// Header refers to the first task of its category so that cursor can be put
+// on header of collapsed category.
+type listRow struct {
+	category string
+	header   bool
+	task     int
+}
+
+// isGrouped returns whether habits list is grouped by category
+// which happens once any not archived task has category set.
+func (model Model) isGrouped() bool {
+	return slices.ContainsFunc(model.tasks.Active(), func(task habit.Task) bool { return task.Category != "" })
+}
+
+// isCollapsed returns whether tasks of category are hidden under its header.
+func (model Model) isCollapsed(category string) bool {
+	_, collapsed := model.collapsed[category]
+
+	return collapsed
+}
+
+// listRows returns habits list lines in display order of current sort
+// mode. Archived tasks and
+// tasks not matching filter label are hidden and when list is grouped tasks
+// are put under their category headers unless category is collapsed.
+func (model Model) listRows() []listRow {
+	rows := []listRow{}
+	visible := func(task habit.Task) bool {
+		return !task.Archived && (model.filter == "" || task.HasLabel(model.filter))
+	}
+
+	order := model.tasks.SortedIndexes(model.sortMode)
+
+	if !model.isGrouped() {
+		for _, idx := range order {
+			if task := model.tasks[idx]; visible(task) {
+				rows = append(rows, listRow{task: idx})
+			}
+		}
+
+		return rows
+	}
+
+	for _, category := range model.tasks.Categories() {
+		members := []int{}
+
+		for _, idx := range order {
+			if task := model.tasks[idx]; task.Category == category && visible(task) {
+				members = append(members, idx)
+			}
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		rows = append(rows, listRow{category: category, header: true, task: members[0]})
+
+		if model.isCollapsed(category) {
+			continue
+		}
+
+		for _, idx := range members {
+			rows = append(rows, listRow{category: category, task: idx})
+		}
+	}
+
+	return rows
+}
+
+// nextStop returns task index of cursor moved by delta lines over tasks
+// and collapsed category headers. Cursor that is hidden is moved to the
+// header of its category or to the first line.
+func (model Model) nextStop(delta int) int {
+	stops := []listRow{}
+
+	for _, row := range model.listRows() {
+		if !row.header || model.isCollapsed(row.category) {
+			stops = append(stops, row)
+		}
+	}
+
+	if len(stops) == 0 {
+		return model.cursorRow
+	}
+
+	current := slices.IndexFunc(stops, func(row listRow) bool { return row.task == model.cursorRow })
+	if current < 0 && model.cursorRow < len(model.tasks) {
+		category := model.tasks[model.cursorRow].Category
+		current = slices.IndexFunc(stops, func(row listRow) bool { return row.header && row.category == category })
+	}
+
+	current = max(0, min(current+delta, len(stops)-1))
+
+	return stops[current].task
+}
+
+// cursorOnHeader returns whether cursor is on collapsed category header.
+func (model Model) cursorOnHeader() bool {
+	return model.hasSelection() && model.isGrouped() && model.isCollapsed(model.tasks[model.cursorRow].Category)
+}
+
+// refreshList drops filter which no task matches anymore and
+// moves cursor to visible line after list has changed.
+func (model Model) refreshList() Model {
+	if !slices.Contains(model.tasks.Labels(), model.filter) {
+		model.filter = ""
+	}
+
+	model.cursorRow = model.nextStop(0)
+
+	return model
+}
+
+// nextFilter returns label following current filter or no filter after the last one.
+func (model Model) nextFilter() string {
+	labels := model.tasks.Labels()
+
+	idx := slices.Index(labels, model.filter)
+	if idx+1 >= len(labels) {
+		return ""
+	}
+
+	return labels[idx+1]
+}
+
+// moveSelected moves selected task by delta lines in manual order. Tasks
+// are moved only within their category and when list is in manual order.
+func (model Model) moveSelected(delta int) Model {
+	if model.sortMode != habit.SortManual {
+		model.message = "switch to manual order with o to move tasks"
+
+		return model
+	}
+
+	neighbour := model.nextStop(delta)
+	if neighbour == model.cursorRow || model.tasks[neighbour].Category != model.tasks[model.cursorRow].Category ||
+		model.cursorOnHeader() {
+		return model
+	}
+
+	swap := newTaskSwap(model.tasks, model.cursorRow, neighbour)
+	model.tasks = swap.apply(model.tasks)
+	model.history.push(swap)
+	model.cursorRow = neighbour
+
+	return model
+}
+
+// listTitle returns title of habits list with active filter and sort mode.
+func (model Model) listTitle() string {
+	details := []string{}
+	if model.filter != "" {
+		details = append(details, model.filter)
+	}
+
+	if model.sortMode != habit.SortManual {
+		details = append(details, model.sortMode.String())
+	}
+
+	if len(details) == 0 {
+		return "Habits:"
+	}
+
+	return fmt.Sprintf("Habits (%s):", strings.Join(details, ", "))
+}
+
+func categoryName(category string) string {
+	if category == "" {
+		return otherCategory
+	}
+
+	return category
+}
+
+// categoryHeader returns header line of category with its week completion.
+func (model Model) categoryHeader(stats habit.CategoryStats) string {
+	arrow := "▾"
+	if model.isCollapsed(stats.Category) {
+		arrow = "▸"
+	}
+
+	return fmt.Sprintf("%s %s (%d/%d this week)", arrow, categoryName(stats.Category), stats.WeekCompletion, stats.WeekTarget)
+}
+
+// categoryDescription returns category statistics shown in description panel.
+func categoryDescription(stats habit.CategoryStats) string {
+	return fmt.Sprintf("Category %s:\nTasks: %d\nCompleted today: %d\nThis week: %d / %d\nThis month: %d / %d",
+		categoryName(stats.Category), stats.Tasks, stats.CompletedToday,
+		stats.WeekCompletion, stats.WeekTarget, stats.MonthCompletion, stats.MonthTarget)
+}
@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+// command is a change of tasks that can be applied again and reverted.
+// Commands keep their own copies of tasks so they have to put clones
+// into the list. Touched returns IDs of tasks which the command replaces.
+type command interface {
+	apply(tasks habit.TaskList) habit.TaskList
+	revert(tasks habit.TaskList) habit.TaskList
+	touched() []string
+}
+
+// taskChange replaces task state before the change with state after it.
+type taskChange struct {
+	before habit.Task
+	after  habit.Task
+}
+
+func (change taskChange) apply(tasks habit.TaskList) habit.TaskList {
+	return replaceTask(tasks, change.after)
+}
+
+func (change taskChange) revert(tasks habit.TaskList) habit.TaskList {
+	return replaceTask(tasks, change.before)
+}
+
+func (change taskChange) touched() []string {
+	return []string{change.after.ID}
+}
+
+// listChange replaces every task of the list e.g. after vacation changed
+// all of them. Tasks added to the list since are left unchanged.
+type listChange struct {
+	before habit.TaskList
+	after  habit.TaskList
+}
+
+func (change listChange) apply(tasks habit.TaskList) habit.TaskList {
+	for _, task := range change.after {
+		tasks = replaceTask(tasks, task)
+	}
+
+	return tasks
+}
+
+func (change listChange) revert(tasks habit.TaskList) habit.TaskList {
+	for _, task := range change.before {
+		tasks = replaceTask(tasks, task)
+	}
+
+	return tasks
+}
+
+func (change listChange) touched() []string {
+	return taskIDs(change.before)
+}
+
+// taskAdd inserts task at index of the list.
+type taskAdd struct {
+	task  habit.Task
+	index int
+}
+
+func (add taskAdd) apply(tasks habit.TaskList) habit.TaskList {
+	return slices.Insert(tasks, min(add.index, len(tasks)), add.task.Clone())
+}
+
+func (add taskAdd) revert(tasks habit.TaskList) habit.TaskList {
+	return removeTask(tasks, add.task.ID)
+}
+
+func (add taskAdd) touched() []string {
+	return []string{add.task.ID}
+}
+
+// taskDelete removes task at index of the list.
+type taskDelete struct {
+	task  habit.Task
+	index int
+}
+
+func (del taskDelete) apply(tasks habit.TaskList) habit.TaskList {
+	return removeTask(tasks, del.task.ID)
+}
+
+func (del taskDelete) revert(tasks habit.TaskList) habit.TaskList {
+	return slices.Insert(tasks, min(del.index, len(tasks)), del.task.Clone())
+}
+
+func (del taskDelete) touched() []string {
+	return []string{del.task.ID}
+}
+
+// taskSwap swaps two tasks in manual order. Swap may number tasks
+// without Position so positions before it are kept for revert.
+type taskSwap struct {
+	one       string
+	other     string
+	positions map[string]int
+}
+
+func newTaskSwap(tasks habit.TaskList, one, other int) taskSwap {
+	positions := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		positions[task.ID] = task.Position
+	}
+
+	return taskSwap{one: tasks[one].ID, other: tasks[other].ID, positions: positions}
+}
+
+func (swap taskSwap) apply(tasks habit.TaskList) habit.TaskList {
+	if one, other := tasks.IndexByID(swap.one), tasks.IndexByID(swap.other); one >= 0 && other >= 0 {
+		tasks.Swap(one, other)
+	}
+
+	return tasks
+}
+
+func (swap taskSwap) revert(tasks habit.TaskList) habit.TaskList {
+	tasks = swap.apply(tasks)
+
+	for idx := range tasks {
+		if position, ok := swap.positions[tasks[idx].ID]; ok {
+			tasks[idx].Position = position
+		}
+	}
+
+	return tasks
+}
+
+func (swap taskSwap) touched() []string {
+	return slices.Collect(maps.Keys(swap.positions))
+}
+
+func replaceTask(tasks habit.TaskList, task habit.Task) habit.TaskList {
+	if idx := tasks.IndexByID(task.ID); idx >= 0 {
+		tasks[idx] = task.Clone()
+	}
+
+	return tasks
+}
+
+func removeTask(tasks habit.TaskList, id string) habit.TaskList {
+	if idx := tasks.IndexByID(id); idx >= 0 {
+		return slices.Delete(tasks, idx, idx+1)
+	}
+
+	return tasks
+}
+
+func taskIDs(tasks habit.TaskList) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	return ids
+}
+
+// changedTasks returns IDs of tasks added, removed or changed in after.
+func changedTasks(before, after habit.TaskList) map[string]struct{} {
+	changed := map[string]struct{}{}
+
+	for _, task := range after {
+		if idx := before.IndexByID(task.ID); idx < 0 || !sameTasks(before[idx], task) {
+			changed[task.ID] = struct{}{}
+		}
+	}
+
+	for _, task := range before {
+		if after.IndexByID(task.ID) < 0 {
+			changed[task.ID] = struct{}{}
+		}
+	}
+
+	return changed
+}
+
+// history keeps commands that changed tasks so that they can be
+// undone and redone until program quits. Changes counts commands
+// pushed, undone and redone so it changes whenever tasks do.
+type history struct {
+	undo    []command
+	redo    []command
+	changes int
+}
+
+// push records command that was just applied. Redo of undone
+// commands is no longer possible after new change.
+func (h *history) push(cmd command) {
+	h.undo = append(h.undo, cmd)
+	h.redo = nil
+	h.changes++
+}
+
+// rebase drops commands touching tasks changed by others so that undo
+// or redo does not overwrite their changes merged into tasks. Commands
+// are undone and redone in order so ones recorded before a dropped
+// command are dropped too.
+func (h *history) rebase(changed map[string]struct{}) {
+	h.undo = commandsAfterTouching(h.undo, changed)
+	h.redo = commandsAfterTouching(h.redo, changed)
+}
+
+// commandsAfterTouching returns commands following the last one
+// which touches any of changed tasks.
+func commandsAfterTouching(cmds []command, changed map[string]struct{}) []command {
+	for idx := len(cmds) - 1; idx >= 0; idx-- {
+		for _, id := range cmds[idx].touched() {
+			if _, ok := changed[id]; ok {
+				return slices.Clone(cmds[idx+1:])
+			}
+		}
+	}
+
+	return cmds
+}
+
+// sameTasks returns whether both tasks would be saved the same way.
+func sameTasks(one, other habit.Task) bool {
+	oneJSON, _ := json.Marshal(one)
+	otherJSON, _ := json.Marshal(other)
+
+	return bytes.Equal(oneJSON, otherJSON)
+}
+
+// change runs fn on task at row and records the change in history.
+func (model Model) change(row int, fn func(task *habit.Task)) {
+	before := model.tasks[row].Clone()
+	fn(&model.tasks[row])
+
+	if after := model.tasks[row].Clone(); !sameTasks(before, after) {
+		model.history.push(taskChange{before: before, after: after})
+	}
+}
+
+// changeAll runs fn that may change any task and records the change in history.
+func (model Model) changeAll(fn func()) {
+	before := model.tasks.Clone()
+	fn()
+
+	if !slices.EqualFunc(before, model.tasks, sameTasks) {
+		model.history.push(listChange{before: before, after: model.tasks.Clone()})
+	}
+}
+
+// undo reverts the last recorded command.
+func (model Model) undo() Model {
+	if len(model.history.undo) == 0 {
+		model.message = "nothing to undo"
+
+		return model
+	}
+
+	cmd := model.history.undo[len(model.history.undo)-1]
+	model.history.undo = model.history.undo[:len(model.history.undo)-1]
+	model.history.redo = append(model.history.redo, cmd)
+	model.history.changes++
+	model.tasks = cmd.revert(model.tasks)
+
+	return model.afterHistoryChange()
+}
+
+// redo applies again the last undone command.
+func (model Model) redo() Model {
+	if len(model.history.redo) == 0 {
+		model.message = "nothing to redo"
+
+		return model
+	}
+
+	cmd := model.history.redo[len(model.history.redo)-1]
+	model.history.redo = model.history.redo[:len(model.history.redo)-1]
+	model.history.undo = append(model.history.undo, cmd)
+	model.history.changes++
+	model.tasks = cmd.apply(model.tasks)
+
+	return model.afterHistoryChange()
+}
+
+// afterHistoryChange refreshes state derived from tasks after undo or redo.
+func (model Model) afterHistoryChange() Model {
+	for row := range model.tasks {
+		model.refreshSelected(row)
+	}
+
+	model.cursorRow = max(0, min(model.cursorRow, len(model.tasks)-1))
+	model.archiveCursor = max(0, min(model.archiveCursor, len(model.archiveRows())-1))
+
+	if model = model.refreshList(); !model.hasSelection() {
+		model.calendarMode = false
+	}
+
+	return model
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bazko1/habitui/habit"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typed returns key messages typing text one rune at a time.
+func typed(text string) []tea.Msg {
+	msgs := []tea.Msg{}
+	for _, r := range text {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	return msgs
+}
+
+func tasksJSON(t *testing.T, tasks habit.TaskList) string {
+	t.Helper()
+
+	b, err := json.Marshal(tasks)
+	if err != nil {
+		t.Fatalf("Failed to marshal tasks: %v", err)
+	}
+
+	return string(b)
+}
+
+func TestModelUndoRedo(t *testing.T) {
+	t.Parallel()
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	tests := []struct {
+		name string
+		msgs []tea.Msg
+	}{
+		{"toggle", []tea.Msg{space}},
+		{"add", append(append(typed("a"), typed("swim")...), enter, typed("x")[0], enter)},
+		{"delete", typed("jdy")},
+		{"edit", append(append(typed("je"), typed("walk")...), enter)},
+		{"swap", typed("J")},
+		{"several", append(typed("J "), typed("kdy")...)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := NewTuiModel(habit.TaskList{habit.NewTask("run", ""), habit.NewTask("read", "")})
+			before := tasksJSON(t, model.tasks)
+
+			for _, msg := range test.msgs {
+				model, _ = update(t, model, msg)
+			}
+
+			after := tasksJSON(t, model.tasks)
+			if after == before {
+				t.Fatal("Keys should change tasks")
+			}
+
+			changes := len(model.history.undo)
+			for range changes {
+				model, _ = update(t, model, typed("u")[0])
+			}
+
+			if got := tasksJSON(t, model.tasks); got != before {
+				t.Fatalf("Undo should restore tasks\n%s\ngot\n%s", before, got)
+			}
+
+			for range changes {
+				model, _ = update(t, model, tea.KeyMsg{Type: tea.KeyCtrlR})
+			}
+
+			if got := tasksJSON(t, model.tasks); got != after {
+				t.Fatalf("Redo should apply changes again\n%s\ngot\n%s", after, got)
+			}
+
+			if model, _ = update(t, model, tea.KeyMsg{Type: tea.KeyCtrlR}); model.message != "nothing to redo" {
+				t.Fatalf("Every change should be redone got message %q", model.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"fmt"
+)
+
+const (
+	defaultBoxWidth = 40
+	minBoxWidth     = 24
+	maxBoxWidth     = 60
+	narrowBoxWidth  = 16
+	// boxBorder is width of left and right border of a box.
+	boxBorder = 2
+	// calendarWidth is width of 7 calendar columns with their borders.
+	calendarWidth = weekDays*5 + weekDays + 1
+	// yearViewWidth is width of year heatmap with day names and border.
+	yearViewWidth = yearLabelWidth + yearWeeks + boxBorder
+	minListHeight = 3
+)
+
+// layout describes sizes of View parts computed from terminal size.
+type layout struct {
+	// boxWidth is inner width of each text box.
+	boxWidth int
+	// wide puts calendar beside text boxes instead of below them.
+	wide bool
+}
+
+// layout returns arrangement that fits current terminal width. Until the
+// size is known default wide layout is used. Text boxes are put side by side
+// with calendar on their right when they fit and calendar goes below them
+// on narrow terminals.
+func (model Model) layout() layout {
+	if model.width == 0 {
+		return layout{boxWidth: defaultBoxWidth, wide: true}
+	}
+
+	calendar := calendarWidth
+	if model.yearView {
+		calendar = yearViewWidth
+	}
+
+	if boxWidth := (model.width-calendar)/2 - boxBorder; boxWidth >= minBoxWidth {
+		return layout{boxWidth: min(boxWidth, maxBoxWidth), wide: true}
+	}
+
+	return layout{boxWidth: max(model.width/2-boxBorder, narrowBoxWidth), wide: false}
+}
+
+// scrollLines returns at most limit lines keeping lines from first up to
+// last visible together with title suffix describing shown part.
+// Zero limit shows all lines.
+func scrollLines(lines []string, first, last, limit int) ([]string, string) {
+	if limit <= 0 || len(lines) <= limit {
+		return lines, ""
+	}
+
+	offset := 0
+	if last >= limit {
+		offset = last - limit + 1
+	}
+
+	offset = min(offset, first)
+
+	return lines[offset : offset+limit], fmt.Sprintf(" [%d-%d/%d]", offset+1, offset+limit, len(lines))
+}
@@ -16,30 +16,82 @@ import (
 	"github.com/muesli/reflow/wrap"
 )
 
+const numWinCols = 2
+
+// editField is a task property other than name or description
+// that is being edited with text input.
+type editField int
+
 const (
-	sectionBoxWidth = 40
-	numWinCols      = 2
+	editFieldNone editField = iota
+	editFieldSchedule
+	editFieldTarget
+	editFieldVacation
+	editFieldNote
+	editFieldLabels
+	editFieldMonth
 )
 
 type Model struct {
-	tasks         habit.TaskList
-	cursorRow     int
-	cursorCol     int
-	selectedRow   map[int]struct{}
-	keys          keyMap
-	editEnabled   bool
-	addingNewTask bool
-	editInput     textinput.Model
-	help          help.Model
+	tasks     habit.TaskList
+	cursorRow int
+	cursorCol int
+	// selected holds IDs of tasks completed today.
+	selected       map[string]struct{}
+	keys           keyMap
+	editEnabled    bool
+	addingNewTask  bool
+	editingField   editField
+	editInput      textinput.Model
+	help           help.Model
+	message        string
+	calendarMode   bool
+	calendarCursor time.Time
+	// calendarMonth is first day of month shown in calendar or zero
+	// time for current month and yearView shows year heatmap instead.
+	calendarMonth time.Time
+	yearView      bool
+	// statsMode shows statistics screen instead of the list.
+	statsMode bool
+	// collapsed holds categories which tasks are hidden in the list
+	// and filter is label that shown tasks have to match.
+	collapsed map[string]struct{}
+	filter    string
+	// archiveMode shows archived tasks instead of the list and
+	// deleting is ID of task waiting for delete confirmation.
+	archiveMode   bool
+	archiveCursor int
+	deleting      string
+	history       *history
+	sortMode      habit.SortMode
+	// width and height are terminal size, zero until it is known.
+	width  int
+	height int
+	// today is the day for which completions are shown and with autosave
+	// tasks are saved to storage when it changes.
+	today    time.Time
+	storage  Storage
+	autosave bool
+	// saving and reloading are set while tasks are saved or reloaded in
+	// background and quitting when program quits once they are done.
+	saving    bool
+	reloading bool
+	quitting  bool
+	saveState saveState
+	// conflict is set once tasks were changed differently in storage.
+	conflict error
 }
 
 func NewTuiModel(tasks habit.TaskList) Model { //nolint:funlen
 	editInput := textinput.New()
 	model := Model{
-		tasks:       tasks,
-		cursorRow:   0,
-		cursorCol:   0,
-		selectedRow: make(map[int]struct{}),
+		tasks:     tasks,
+		cursorRow: 0,
+		cursorCol: 0,
+		selected:  make(map[string]struct{}),
+		collapsed: make(map[string]struct{}),
+		history:   &history{},
+		today:     dateOf(habit.Now()),
 		keys: keyMap{
 			Up: key.NewBinding(
 				key.WithKeys("up", "k"),
@@ -77,6 +129,106 @@ func NewTuiModel(tasks habit.TaskList) Model { //nolint:funlen
 				key.WithKeys("e"),
 				key.WithHelp("e", "edit task short name or description"),
 			),
+			Schedule: key.NewBinding(
+				key.WithKeys("p"),
+				key.WithHelp("p", "edit task schedule e.g. daily, every 2 days, 3/week, mon,wed,fri"),
+			),
+			Target: key.NewBinding(
+				key.WithKeys("t"),
+				key.WithHelp("t", "set daily target e.g. 8 glasses, 0 for done or not done task"),
+			),
+			Increment: key.NewBinding(
+				key.WithKeys("+", "="),
+				key.WithHelp("+", "increase today's amount"),
+			),
+			Decrement: key.NewBinding(
+				key.WithKeys("-"),
+				key.WithHelp("-", "decrease today's amount"),
+			),
+			QuitHabit: key.NewBinding(
+				key.WithKeys("x"),
+				key.WithHelp("x", "toggle task as bad habit to quit, then Enter records relapse"),
+			),
+			Skip: key.NewBinding(
+				key.WithKeys("s"),
+				key.WithHelp("s", "skip today (or calendar day) without breaking strike"),
+			),
+			Vacation: key.NewBinding(
+				key.WithKeys("V"),
+				key.WithHelp("V", "add or remove vacation for all tasks e.g. 2024-07-01 2024-07-14"),
+			),
+			Note: key.NewBinding(
+				key.WithKeys("n"),
+				key.WithHelp("n", "add note to today's (or calendar day) completion e.g. 4 | ran 5k"),
+			),
+			Labels: key.NewBinding(
+				key.WithKeys("g"),
+				key.WithHelp("g", "set task category and tags e.g. health: morning, outdoor"),
+			),
+			Filter: key.NewBinding(
+				key.WithKeys("f"),
+				key.WithHelp("f", "filter tasks by next category or tag"),
+			),
+			Collapse: key.NewBinding(
+				key.WithKeys("z"),
+				key.WithHelp("z", "collapse or expand selected task category"),
+			),
+			Pause: key.NewBinding(
+				key.WithKeys("P"),
+				key.WithHelp("P", "pause or resume task without breaking strike"),
+			),
+			Archive: key.NewBinding(
+				key.WithKeys("A"),
+				key.WithHelp("A", "archive task keeping its history"),
+			),
+			Archives: key.NewBinding(
+				key.WithKeys("b"),
+				key.WithHelp("b", "browse archived tasks to restore or delete them"),
+			),
+			MoveUp: key.NewBinding(
+				key.WithKeys("K", "shift+up"),
+				key.WithHelp("K", "move task up in manual order"),
+			),
+			MoveDown: key.NewBinding(
+				key.WithKeys("J", "shift+down"),
+				key.WithHelp("J", "move task down in manual order"),
+			),
+			Sort: key.NewBinding(
+				key.WithKeys("o"),
+				key.WithHelp("o", "change order: manual, name, strike, completion, not done first"),
+			),
+			Undo: key.NewBinding(
+				key.WithKeys("u"),
+				key.WithHelp("u", "undo last change"),
+			),
+			Redo: key.NewBinding(
+				key.WithKeys("ctrl+r"),
+				key.WithHelp("ctrl+r", "redo undone change"),
+			),
+			Calendar: key.NewBinding(
+				key.WithKeys("c"),
+				key.WithHelp("c", "toggle calendar mode to change past days"),
+			),
+			PrevMonth: key.NewBinding(
+				key.WithKeys("["),
+				key.WithHelp("[", "show previous month in calendar"),
+			),
+			NextMonth: key.NewBinding(
+				key.WithKeys("]"),
+				key.WithHelp("]", "show next month in calendar"),
+			),
+			Month: key.NewBinding(
+				key.WithKeys("m"),
+				key.WithHelp("m", "jump to month e.g. 2024-03"),
+			),
+			Year: key.NewBinding(
+				key.WithKeys("y"),
+				key.WithHelp("y", "toggle year view of completions"),
+			),
+			Stats: key.NewBinding(
+				key.WithKeys("S"),
+				key.WithHelp("S", "show statistics of habits"),
+			),
 			Quit: key.NewBinding(
 				key.WithKeys("q", "esc", "ctrl+c"),
 				key.WithHelp("q", "quit"),
@@ -88,9 +240,9 @@ func NewTuiModel(tasks habit.TaskList) Model { //nolint:funlen
 		help:          help.New(),
 	}
 
-	for tID, t := range tasks {
+	for _, t := range tasks {
 		if t.WasCompletedToday() {
-			model.selectedRow[tID] = struct{}{}
+			model.selected[t.ID] = struct{}{}
 		}
 	}
 
@@ -98,7 +250,11 @@ func NewTuiModel(tasks habit.TaskList) Model { //nolint:funlen
 }
 
 func (model Model) Init() tea.Cmd {
-	return textinput.Blink
+	if model.storage != nil {
+		return tea.Batch(textinput.Blink, tick(), watch())
+	}
+
+	return tea.Batch(textinput.Blink, tick())
 }
 
 func (model Model) Tasks() habit.TaskList {
@@ -106,16 +262,41 @@ func (model Model) Tasks() habit.TaskList {
 }
 
 type keyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Left   key.Binding
-	Right  key.Binding
-	Select key.Binding
-	Help   key.Binding
-	Add    key.Binding
-	Delete key.Binding
-	Edit   key.Binding
-	Quit   key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Select    key.Binding
+	Help      key.Binding
+	Add       key.Binding
+	Delete    key.Binding
+	Edit      key.Binding
+	Schedule  key.Binding
+	Target    key.Binding
+	Increment key.Binding
+	Decrement key.Binding
+	QuitHabit key.Binding
+	Skip      key.Binding
+	Vacation  key.Binding
+	Note      key.Binding
+	Labels    key.Binding
+	Filter    key.Binding
+	Collapse  key.Binding
+	Pause     key.Binding
+	Archive   key.Binding
+	Archives  key.Binding
+	MoveUp    key.Binding
+	MoveDown  key.Binding
+	Sort      key.Binding
+	Undo      key.Binding
+	Redo      key.Binding
+	Calendar  key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Month     key.Binding
+	Year      key.Binding
+	Stats     key.Binding
+	Quit      key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
@@ -126,7 +307,15 @@ func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Help, k.Quit, k.Select, k.Add},
-		{k.Edit, k.Delete},
+		{k.Edit, k.Delete, k.Schedule, k.Calendar},
+		{k.Target, k.Increment, k.Decrement, k.QuitHabit},
+		{k.Skip, k.Vacation, k.Note},
+		{k.Labels, k.Filter, k.Collapse},
+		{k.Pause, k.Archive, k.Archives},
+		{k.MoveUp, k.MoveDown, k.Sort},
+		{k.Undo, k.Redo},
+		{k.PrevMonth, k.NextMonth, k.Month, k.Year},
+		{k.Stats},
 	}
 }
 
@@ -142,21 +331,63 @@ var editMap = struct { //nolint:gochecknoglobals
 		key.WithHelp("enter", "confirm")),
 }
 
-func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn, funlen, cyclop,gocognit
-	switch msg := msg.(type) { //nolint: gocritic
+func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn
+	changes := model.history.changes
+	updated, cmd := model.update(msg)
+
+	return updated.afterChange(changes, cmd)
+}
+
+func (model Model) update(msg tea.Msg) (Model, tea.Cmd) { //nolint: funlen, cyclop,gocognit
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		model.width, model.height = msg.Width, msg.Height
+	case tickMsg:
+		return model.updateTick()
+	case watchMsg:
+		return model.updateWatch()
+	case saveDueMsg:
+		return model.updateSaveDue(msg)
+	case savedMsg:
+		return model.updateSaved(msg)
+	case reloadedMsg:
+		return model.updateReloaded(msg)
 	case tea.KeyMsg:
+		model.message = ""
+
+		if model.deleting != "" {
+			return model.updateDeleteConfirmation(msg), nil
+		}
+
 		if model.editEnabled {
 			switch {
 			case key.Matches(msg, editMap.Quit):
 				model.editEnabled = false
+				model.editingField = editFieldNone
 				model.editInput.Blur()
 				model.editInput.Reset()
 			case key.Matches(msg, editMap.Confirm):
 				model.editEnabled = false
 
-				if model.cursorCol == 0 {
+				if model.editingField != editFieldNone {
+					var err error
+					if model.editingField == editFieldMonth {
+						model, err = model.jumpToMonth(model.editInput.Value())
+					} else {
+						err = model.applyFieldEdit(model.editInput.Value())
+					}
+
+					if err != nil {
+						model.message = err.Error()
+					}
+
+					model = model.refreshList()
+
+					model.editingField = editFieldNone
+					model.editInput.Blur()
+				} else if model.cursorCol == 0 {
 					if val := model.editInput.Value(); val != "" {
-						model.tasks[model.cursorRow].Name = val
+						model.change(model.cursorRow, func(task *habit.Task) { task.Name = val })
 					}
 
 					if model.addingNewTask {
@@ -166,7 +397,7 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn,
 					}
 				} else {
 					if val := model.editInput.Value(); val != "" {
-						model.tasks[model.cursorRow].Description = val
+						model.change(model.cursorRow, func(task *habit.Task) { task.Description = val })
 					}
 					model.cursorCol = 0
 					model.editInput.Blur()
@@ -181,56 +412,113 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn,
 			return model, cmd
 		}
 
+		switch {
+		case key.Matches(msg, model.keys.Undo):
+			return model.undo(), nil
+		case key.Matches(msg, model.keys.Redo):
+			return model.redo(), nil
+		}
+
+		if model.calendarMode {
+			return model.updateCalendarMode(msg), nil
+		}
+
+		if model.archiveMode {
+			return model.updateArchiveMode(msg), nil
+		}
+
+		if model.statsMode {
+			return model.updateStatsMode(msg), nil
+		}
+
 		switch {
 		case key.Matches(msg, model.keys.Quit):
+			// tasks merged by storage have to be shown before quitting
+			// so that they are saved on exit
+			if model.saving || model.reloading {
+				model.quitting = true
+
+				return model, nil
+			}
+
 			return model, tea.Quit
 
+		case model.cursorOnHeader() && key.Matches(msg, model.keys.Select):
+			delete(model.collapsed, model.tasks[model.cursorRow].Category)
+
+		case model.cursorOnHeader() && !key.Matches(msg, model.keys.Up, model.keys.Down, model.keys.Help,
+			model.keys.Add, model.keys.Vacation, model.keys.Filter, model.keys.Collapse, model.keys.Archives):
+			// tasks of collapsed category have to be expanded to be changed
+
 		case key.Matches(msg, model.keys.Up):
-			if model.cursorRow > 0 && model.cursorCol == 0 {
-				model.cursorRow--
+			if model.cursorCol == 0 {
+				model.cursorRow = model.nextStop(-1)
 			}
 
 		case key.Matches(msg, model.keys.Down):
-			if model.cursorRow < len(model.tasks)-1 && model.cursorCol == 0 {
-				model.cursorRow++
+			if model.cursorCol == 0 {
+				model.cursorRow = model.nextStop(1)
 			}
 
 		case key.Matches(msg, model.keys.Right):
-			if model.cursorCol < numWinCols && len(model.tasks) > 0 {
+			if model.cursorCol < numWinCols && model.hasSelection() {
 				model.cursorCol++
 			}
 
 		case key.Matches(msg, model.keys.Left):
-			if model.cursorCol > 0 && len(model.tasks) > 0 {
+			if model.cursorCol > 0 && model.hasSelection() {
 				model.cursorCol--
 			}
 
 		case key.Matches(msg, model.keys.Select):
-			_, ok := model.selectedRow[model.cursorRow]
-			if ok {
-				delete(model.selectedRow, model.cursorRow)
-				model.tasks[model.cursorRow].MakeUnCompleted()
-			} else {
-				model.selectedRow[model.cursorRow] = struct{}{}
-				model.tasks[model.cursorRow].MakeCompleted()
+			if !model.hasSelection() {
+				break
+			}
+
+			model.change(model.cursorRow, func(task *habit.Task) {
+				if _, ok := model.selected[task.ID]; ok {
+					delete(model.selected, task.ID)
+					task.MakeUnCompleted()
+				} else {
+					model.selected[task.ID] = struct{}{}
+					task.MakeCompleted()
+				}
+			})
+
+		case key.Matches(msg, model.keys.Increment), key.Matches(msg, model.keys.Decrement):
+			if !model.hasSelection() || model.tasks[model.cursorRow].Kind != habit.TaskKindQuantity {
+				break
 			}
 
+			delta := 1
+			if key.Matches(msg, model.keys.Decrement) {
+				delta = -1
+			}
+
+			model.change(model.cursorRow, func(task *habit.Task) { task.AddAmount(delta) })
+			model.refreshSelected(model.cursorRow)
+
 		case key.Matches(msg, model.keys.Add):
-			model.tasks = append(model.tasks, habit.NewTask("Set name", "Set description"))
-			model.cursorRow = len(model.tasks) - 1
+			task := model.tasks.WithVacations(habit.NewTask("Set name", "Set description"))
+			add := taskAdd{task: task, index: len(model.tasks)}
+			model.tasks = add.apply(model.tasks)
+			model.history.push(add)
+			model.cursorRow = add.index
+			model.filter = ""
+			delete(model.collapsed, "")
 			model.editEnabled = true
 			model.addingNewTask = true
 			model.editInput.Focus()
 			model.editInput.Cursor.Blink = true
 
 		case key.Matches(msg, model.keys.Delete):
-			model.tasks = slices.Delete(model.tasks, model.cursorRow, model.cursorRow+1)
-
-			if model.cursorRow > 0 {
-				model.cursorRow--
+			if !model.hasSelection() {
+				break
 			}
+
+			model = model.askDelete(model.cursorRow)
 		case key.Matches(msg, model.keys.Edit):
-			if len(model.tasks) == 0 {
+			if !model.hasSelection() {
 				break
 			}
 
@@ -239,6 +527,117 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn,
 				model.editInput.Focus()
 				model.editInput.Cursor.Blink = true
 			}
+		case key.Matches(msg, model.keys.Skip):
+			if !model.hasSelection() {
+				break
+			}
+
+			model.toggleSkip(model.tasks[model.cursorRow].Now())
+		case key.Matches(msg, model.keys.Schedule), key.Matches(msg, model.keys.Target),
+			key.Matches(msg, model.keys.Vacation), key.Matches(msg, model.keys.Note),
+			key.Matches(msg, model.keys.Labels), key.Matches(msg, model.keys.Month):
+			if !model.hasSelection() {
+				break
+			}
+
+			switch {
+			case key.Matches(msg, model.keys.Target):
+				model.editingField = editFieldTarget
+			case key.Matches(msg, model.keys.Vacation):
+				model.editingField = editFieldVacation
+			case key.Matches(msg, model.keys.Note):
+				model.editingField = editFieldNote
+			case key.Matches(msg, model.keys.Labels):
+				model.editingField = editFieldLabels
+			case key.Matches(msg, model.keys.Month):
+				model.editingField = editFieldMonth
+			default:
+				model.editingField = editFieldSchedule
+			}
+
+			model.editEnabled = true
+			model.editInput.Focus()
+			model.editInput.Cursor.Blink = true
+		case key.Matches(msg, model.keys.QuitHabit):
+			if !model.hasSelection() {
+				break
+			}
+
+			model.change(model.cursorRow, func(task *habit.Task) {
+				if err := task.SetQuit(task.Kind != habit.TaskKindQuit); err != nil {
+					model.message = "can not change kind of task that was already completed, add new task instead"
+				}
+			})
+			model.refreshSelected(model.cursorRow)
+		case key.Matches(msg, model.keys.Calendar):
+			if !model.hasSelection() {
+				break
+			}
+
+			model.calendarMode = true
+			model.calendarCursor = model.clampCursor(model.tasks[model.cursorRow], model.tasks[model.cursorRow].Now())
+		case key.Matches(msg, model.keys.PrevMonth), key.Matches(msg, model.keys.NextMonth),
+			key.Matches(msg, model.keys.Year):
+			if !model.hasSelection() {
+				break
+			}
+
+			model = model.updateCalendarView(msg)
+		case key.Matches(msg, model.keys.Pause):
+			if !model.hasSelection() {
+				break
+			}
+
+			model.change(model.cursorRow, func(task *habit.Task) {
+				if task.IsPaused() {
+					task.Resume()
+				} else {
+					task.Pause()
+				}
+			})
+
+			model.refreshSelected(model.cursorRow)
+		case key.Matches(msg, model.keys.Archive):
+			if !model.hasSelection() {
+				break
+			}
+
+			model = model.archiveSelected()
+		case key.Matches(msg, model.keys.Archives):
+			model.archiveMode = true
+			model.cursorCol = 0
+		case key.Matches(msg, model.keys.Stats):
+			model.statsMode = model.hasSelection()
+			model.cursorCol = 0
+		case key.Matches(msg, model.keys.MoveUp), key.Matches(msg, model.keys.MoveDown):
+			if !model.hasSelection() {
+				break
+			}
+
+			delta := 1
+			if key.Matches(msg, model.keys.MoveUp) {
+				delta = -1
+			}
+
+			model = model.moveSelected(delta)
+		case key.Matches(msg, model.keys.Sort):
+			model.sortMode = model.sortMode.Next()
+			model = model.refreshList()
+		case key.Matches(msg, model.keys.Filter):
+			model.filter = model.nextFilter()
+			model = model.refreshList()
+		case key.Matches(msg, model.keys.Collapse):
+			if !model.hasSelection() || !model.isGrouped() {
+				break
+			}
+
+			if category := model.tasks[model.cursorRow].Category; model.isCollapsed(category) {
+				delete(model.collapsed, category)
+			} else {
+				model.collapsed[category] = struct{}{}
+			}
+
+			model = model.refreshList()
 		case key.Matches(msg, model.keys.Help):
 			model.help.ShowAll = !model.help.ShowAll
 		}
@@ -247,6 +646,184 @@ func (model Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nolint: ireturn,
 	return model, nil
 }
 
+// applyFieldEdit sets edited property of the selected task from input text.
+func (model Model) applyFieldEdit(text string) error { //nolint:cyclop
+	row := model.cursorRow
+
+	switch model.editingField {
+	case editFieldSchedule:
+		schedule, err := habit.ParseSchedule(text)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		model.change(row, func(task *habit.Task) {
+			task.Schedule = schedule
+			task.RecomputeStats()
+		})
+	case editFieldTarget:
+		target, unit, err := habit.ParseTarget(text)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		model.change(row, func(task *habit.Task) { err = task.SetTarget(target, unit) })
+		model.refreshSelected(row)
+
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	case editFieldVacation:
+		vacation, err := habit.ParseDateRange(text, model.tasks[row].Now().Location())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		model.changeAll(func() { model.tasks.ToggleVacation(vacation) })
+
+		for row := range model.tasks {
+			model.refreshSelected(row)
+		}
+	case editFieldNote:
+		note, err := habit.ParseNote(text)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		model.change(row, func(task *habit.Task) { err = task.SetNoteAt(model.noteDate(), note) })
+
+		return err //nolint:wrapcheck
+	case editFieldLabels:
+		model.change(row, func(task *habit.Task) { task.Category, task.Tags = habit.ParseLabels(text) })
+	case editFieldNone:
+	}
+
+	return nil
+}
+
+// noteDate returns day which notes are shown and edited for that is
+// calendar cursor day in calendar mode and today otherwise.
+func (model Model) noteDate() time.Time {
+	if model.calendarMode {
+		return model.calendarCursor
+	}
+
+	return model.tasks[model.cursorRow].Now()
+}
+
+// noteInput formats note the way it is parsed by habit.ParseNote.
+func noteInput(note habit.Note) string {
+	if note.Mood == 0 {
+		return note.Text
+	}
+
+	return fmt.Sprintf("%d | %s", note.Mood, note.Text)
+}
+
+// toggleSkip skips selected task at given date or removes skip mark if it was skipped.
+func (model Model) toggleSkip(date time.Time) {
+	model.change(model.cursorRow, func(task *habit.Task) {
+		if task.IsSkipped(date) {
+			task.UnSkipAt(date)
+		} else {
+			task.SkipAt(date)
+		}
+	})
+
+	model.refreshSelected(model.cursorRow)
+}
+
+// refreshSelected marks task at row as selected if task was completed today.
+func (model Model) refreshSelected(row int) {
+	task := model.tasks[row]
+	if task.WasCompletedToday() {
+		model.selected[task.ID] = struct{}{}
+	} else {
+		delete(model.selected, task.ID)
+	}
+}
+
+// updateCalendarView handles keys that change month or view shown in calendar.
+func (model Model) updateCalendarView(msg tea.KeyMsg) Model {
+	switch {
+	case key.Matches(msg, model.keys.PrevMonth):
+		model = model.pageMonth(-1)
+	case key.Matches(msg, model.keys.NextMonth):
+		model = model.pageMonth(1)
+	case key.Matches(msg, model.keys.Year):
+		model.yearView = !model.yearView
+		if model.calendarMode {
+			model.calendarCursor = model.clampCursor(model.tasks[model.cursorRow], model.calendarCursor)
+		}
+	}
+
+	return model
+}
+
+// updateCalendarMode handles keys when calendar day cursor is active.
+// Cursor moves over shown days up to today paging months when it leaves
+// shown month and selecting a day toggles selected task completion at
+// that date. In year view weeks are columns so cursor moves by days
+// vertically and by weeks horizontally.
+func (model Model) updateCalendarMode(msg tea.KeyMsg) Model { //nolint:cyclop
+	cursor := model.calendarCursor
+	vertical, horizontal := weekDays, 1
+
+	if model.yearView {
+		vertical, horizontal = 1, weekDays
+	}
+
+	switch {
+	case key.Matches(msg, model.keys.Quit), key.Matches(msg, model.keys.Calendar):
+		model.calendarMode = false
+	case key.Matches(msg, model.keys.Up):
+		cursor = cursor.AddDate(0, 0, -vertical)
+	case key.Matches(msg, model.keys.Down):
+		cursor = cursor.AddDate(0, 0, vertical)
+	case key.Matches(msg, model.keys.Left):
+		cursor = cursor.AddDate(0, 0, -horizontal)
+	case key.Matches(msg, model.keys.Right):
+		cursor = cursor.AddDate(0, 0, horizontal)
+	case key.Matches(msg, model.keys.PrevMonth), key.Matches(msg, model.keys.NextMonth),
+		key.Matches(msg, model.keys.Year):
+		return model.updateCalendarView(msg)
+	case key.Matches(msg, model.keys.Month):
+		model.editingField = editFieldMonth
+		model.editEnabled = true
+		model.editInput.Focus()
+		model.editInput.Cursor.Blink = true
+	case key.Matches(msg, model.keys.Select):
+		model.change(model.cursorRow, func(task *habit.Task) {
+			if task.WasCompletedAt(cursor.Date()) {
+				task.MakeUnCompletedAt(cursor)
+			} else {
+				task.MakeCompletedAt(cursor)
+			}
+		})
+
+		model.refreshSelected(model.cursorRow)
+	case key.Matches(msg, model.keys.Skip):
+		model.toggleSkip(cursor)
+	case key.Matches(msg, model.keys.Note):
+		model.editingField = editFieldNote
+		model.editEnabled = true
+		model.editInput.Focus()
+		model.editInput.Cursor.Blink = true
+	case key.Matches(msg, model.keys.Help):
+		model.help.ShowAll = !model.help.ShowAll
+	}
+
+	task := model.tasks[model.cursorRow]
+	if first, last := model.calendarRange(task); !model.yearView &&
+		(dateOf(cursor).Before(first) || dateOf(cursor).After(last)) {
+		model = model.showMonth(cursor)
+	}
+
+	model.calendarCursor = model.clampCursor(task, cursor)
+
+	return model
+}
+
 func formatSelectedText(text string) string {
 	style := lipgloss.NewStyle().
 		Bold(true).
@@ -256,30 +833,30 @@ func formatSelectedText(text string) string {
 	return style.Render(text)
 }
 
-func createUpperTextPanelBox(text string, height int) string {
+func createUpperTextPanelBox(text string, height, width int) string {
 	style := lipgloss.NewStyle().
 		PaddingTop(0).
 		PaddingLeft(0).
 		Border(lipgloss.NormalBorder()).
 		Height(height).
-		Width(sectionBoxWidth)
+		Width(width)
 
 	return style.Render(text)
 }
 
-func createDescriptionBox(desc string, height int, selected bool) string {
+func createDescriptionBox(desc string, height, width int, selected bool) string {
 	style := lipgloss.NewStyle().
 		PaddingTop(0).
 		PaddingLeft(0).
 		Border(lipgloss.NormalBorder()).
 		Height(height).
-		Width(sectionBoxWidth)
+		Width(width)
 
 	// width based wrapping seems to
 	// work incorrect if we want to
 	// format text previously
-	desc = wordwrap.String(desc, sectionBoxWidth)
-	desc = wrap.String(desc, sectionBoxWidth) // force-wrap long strings
+	desc = wordwrap.String(desc, width)
+	desc = wrap.String(desc, width) // force-wrap long strings
 
 	if selected {
 		desc = formatSelectedText(desc)
@@ -288,38 +865,90 @@ func createDescriptionBox(desc string, height int, selected bool) string {
 	return style.Render("Description:\n" + desc)
 }
 
-func createLowerPanelTextBox(text string, height int) string {
+func createLowerPanelTextBox(text string, height, width int) string {
 	style := lipgloss.NewStyle().
 		PaddingTop(0).
 		PaddingLeft(0).
 		Border(lipgloss.NormalBorder()).
 		Height(height).
-		Width(sectionBoxWidth)
+		Width(width)
 
 	return style.Render(text)
 }
 
-func (model Model) View() string { //nolint:funlen
+func (model Model) View() string { //nolint:funlen,gocognit,cyclop
+	if model.statsMode {
+		model.help.Width = model.width
+
+		return model.statsView() + "\n" + model.help.View(model.keys)
+	}
+
+	lay := model.layout()
 	description := ""
-	habits := strings.Builder{}
+	title := model.listTitle()
+	lines := []string{}
+	cursorFirst, cursorLast := 0, 0
 	selectedID := 0
 	descriptionSelected := false
-	height := 1
-	minHeight := 3
 
-	habits.WriteString("Habits:\n")
+	rows := model.listRows()
+
+	if model.archiveMode {
+		rows = model.archiveRows()
+		if len(rows) > 0 {
+			model.cursorRow = rows[model.archiveCursor].task
+		}
+
+		title = "Archive:"
+	}
+
+	categoryStats := model.tasks.CategoryStats()
+
+	for _, row := range rows {
+		if row.header {
+			stats := categoryStats[slices.IndexFunc(categoryStats,
+				func(stats habit.CategoryStats) bool { return stats.Category == row.category })]
+			header := wrap.String(model.categoryHeader(stats), lay.boxWidth)
+			header = lipgloss.NewStyle().Bold(true).Render(header)
+
+			if model.cursorRow == row.task && model.isCollapsed(row.category) {
+				selectedID = row.task
+				description = categoryDescription(stats)
+				cursorFirst = len(lines)
 
-	for taskID, task := range model.tasks {
+				if model.cursorCol == 0 {
+					header = formatSelectedText(header)
+				}
+			}
+
+			lines = append(lines, strings.Split(header, "\n")...)
+
+			if selectedID == row.task && model.isCollapsed(row.category) {
+				cursorLast = len(lines) - 1
+			}
+
+			continue
+		}
+
+		taskID, task := row.task, model.tasks[row.task]
 		taskName := task.Name
-		height += (len(taskName)/sectionBoxWidth + 2)
 		taskSelected := false
 
 		if model.cursorRow == taskID {
 			selectedID = taskID
 			description = task.Description
+			cursorFirst = len(lines)
+
+			if note, ok := task.NoteAt(model.noteDate()); ok {
+				description += fmt.Sprintf("\n\nNote %s:\n%s", model.noteDate().Format(time.DateOnly), note)
+			}
+
+			if task.Archived {
+				description = archivedDescription(task)
+			}
 
 			if model.cursorCol == 0 {
-				if model.editEnabled {
+				if model.editEnabled && model.editingField == editFieldNone {
 					model.editInput.Placeholder = taskName
 					taskName = model.editInput.View()
 				} else {
@@ -329,26 +958,49 @@ func (model Model) View() string { //nolint:funlen
 		}
 
 		completed := " "
-		if _, ok := model.selectedRow[taskID]; ok {
+		if _, ok := model.selected[task.ID]; ok {
 			completed = "x"
 		}
 
-		if len(taskName)+4 >= sectionBoxWidth {
-			//// nolint:gomnd // 4 is for the completion box plus space
-			taskName = wrap.String(taskName, sectionBoxWidth-4)
+		if completed == " " && task.IsSkipped(task.Now()) {
+			completed = "-"
+		}
+
+		switch task.Kind {
+		case habit.TaskKindQuantity:
+			completed = fmt.Sprintf("%d/%d", task.AmountToday(), task.Target)
+		case habit.TaskKindQuit:
+			if completed == "x" {
+				completed = "!"
+			}
+		case habit.TaskKindCheck:
+		}
+
+		paused := ""
+		if task.IsPaused() {
+			paused = " (paused)"
+		}
+
+		// completion box plus space
+		if boxWidth := len(completed) + len(paused) + 3; len(taskName)+boxWidth >= lay.boxWidth {
+			taskName = wrap.String(taskName, lay.boxWidth-boxWidth)
 		}
 
 		if taskSelected {
 			taskName = formatSelectedText(taskName)
 		}
 
-		text := fmt.Sprintf("[%s] %s", completed, taskName)
+		text := fmt.Sprintf("[%s] %s%s", completed, taskName, paused)
+
+		lines = append(lines, strings.Split(text, "\n")...)
 
-		habits.WriteString(lipgloss.NewStyle().Render(text) + "\n")
+		if model.cursorRow == taskID {
+			cursorLast = len(lines) - 1
+		}
 	}
 
 	if model.cursorCol == 1 {
-		if model.editEnabled {
+		if model.editEnabled && model.editingField == editFieldNone {
 			model.editInput.Placeholder = description
 			description = model.editInput.View()
 		} else {
@@ -356,68 +1008,142 @@ func (model Model) View() string { //nolint:funlen
 		}
 	}
 
-	view := ""
+	switch {
+	case len(rows) == 0 && model.archiveMode:
+		lines = append(lines, "No archived habits.")
 
-	if len(model.tasks) == 0 {
-		habits.WriteString("No habits.")
+		description = "Archived habits are kept with their history until deleted."
+	case len(rows) == 0:
+		lines = append(lines, "No habits.")
 
 		description = "Add new task and start forming habit."
 	}
 
-	if height < minHeight {
-		height = minHeight
-	}
-
-	view += lipgloss.JoinHorizontal(lipgloss.Right,
-		createUpperTextPanelBox(strings.TrimSuffix(habits.String(), "\n"), height),
-		createDescriptionBox(description, height, descriptionSelected))
+	lowerPanel := ""
+	calendar := ""
 
-	if len(model.tasks) != 0 {
-		dateToday := model.tasks[0].GetTime()
+	if len(rows) != 0 {
 		selectedTask := model.tasks[selectedID]
-		numOfStats := 4
+		numOfStats := 7
+		weekTarget, monthTarget, yearTarget := selectedTask.AllTarget()
+
+		completionTitle := fmt.Sprintf("Completion (%s):", selectedTask.Schedule)
+		if selectedTask.Kind == habit.TaskKindQuantity {
+			completionTitle = fmt.Sprintf("Completion (%s, %s):", selectedTask.Schedule, selectedTask.TargetString())
+		}
+
+		switch model.editingField {
+		case editFieldSchedule:
+			model.editInput.Placeholder = selectedTask.Schedule.String()
+			completionTitle = "Schedule: " + model.editInput.View()
+		case editFieldTarget:
+			model.editInput.Placeholder = selectedTask.TargetString()
+			completionTitle = "Target: " + model.editInput.View()
+		case editFieldVacation:
+			model.editInput.Placeholder = "YYYY-MM-DD YYYY-MM-DD"
+			completionTitle = "Vacation: " + model.editInput.View()
+		case editFieldNote:
+			note, _ := selectedTask.NoteAt(model.noteDate())
+			model.editInput.Placeholder = "[1-5 |] note"
+			if !note.IsEmpty() {
+				model.editInput.Placeholder = noteInput(note)
+			}
+			completionTitle = "Note: " + model.editInput.View()
+		case editFieldLabels:
+			model.editInput.Placeholder = "category: tag, tag"
+			if labels := selectedTask.LabelsString(); labels != "" {
+				model.editInput.Placeholder = labels
+			}
+			completionTitle = "Category: " + model.editInput.View()
+		case editFieldMonth:
+			model.editInput.Placeholder = monthLayout
+			completionTitle = "Month: " + model.editInput.View()
+		case editFieldNone:
+		}
+
+		monthAmount := ""
+		if selectedTask.Kind == habit.TaskKindQuantity {
+			y, m, _ := selectedTask.Now().Date()
+			monthAmount = fmt.Sprintf(" (%d %s)", selectedTask.MonthAmount(y, m), selectedTask.Unit)
+		}
+
+		strikeTitle := "Strike:"
+		completionText := fmt.Sprintf("%s\n\tThis week: %d / %d\n\tThis month: %d / %d%s\n\tThis year: %d / %d",
+			completionTitle,
+			selectedTask.CurrentWeekCompletion(), weekTarget,
+			selectedTask.CurrentMonthCompletion(), monthTarget, monthAmount,
+			selectedTask.CurrentYearCompletion(), yearTarget)
+
+		if selectedTask.Kind == habit.TaskKindQuit {
+			strikeTitle = "Clean days:"
+			completionText = fmt.Sprintf("Relapses:\n\tThis week: %d\n\tThis month: %d\n\tThis year: %d",
+				selectedTask.CurrentWeekCompletion(),
+				selectedTask.CurrentMonthCompletion(),
+				selectedTask.CurrentYearCompletion())
+		}
 
-		lowerPanel := lipgloss.JoinHorizontal(
-			1,
-			createLowerPanelTextBox(fmt.Sprintf("Strike:\n\tCurrent: %d\n\tBest monthly: %d\n\tBest yearly: %d",
+		lowerPanel = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			createLowerPanelTextBox(fmt.Sprintf("%s\n\tCurrent: %d\n\tThis week: %d\n\tThis month: %d"+
+				"\n\tBest monthly: %d\n\tBest yearly: %d\n\tBest ever: %d",
+				strikeTitle,
 				selectedTask.CurrentStrike(),
+				selectedTask.CurrentWeekStrike(),
+				selectedTask.CurrentMonthStrike(),
 				selectedTask.CurrentMonthBestStrike(),
-				selectedTask.CurrentYearBestStrike()), numOfStats),
-
-			createLowerPanelTextBox(
-				fmt.Sprintf("Completion:\n\tThis week: %d / 7\n\tThis month: %d / %d \n\tThis year: %d / %d",
-					selectedTask.CurrentWeekCompletion(),
-					selectedTask.CurrentMonthCompletion(), getDaysInMonth(dateToday),
-					selectedTask.CurrentYearCompletion(), getDaysInYear(dateToday)),
-				numOfStats),
+				selectedTask.CurrentYearBestStrike(),
+				selectedTask.BestStrike()), numOfStats, lay.boxWidth),
+
+			createLowerPanelTextBox(completionText, numOfStats, lay.boxWidth),
 		)
 
-		view = lipgloss.JoinVertical(1, view, lowerPanel)
+		calendar = model.calendarView(selectedTask)
+	}
+
+	model.help.Width = model.width
+	helpView := model.help.View(model.keys)
+
+	// habits list scrolls when it does not fit between date label,
+	// box borders, lower panel, help and message lines
+	listHeight := 0
+	if model.height > 0 {
+		reserved := 1 + boxBorder + 1 + lipgloss.Height(lowerPanel) + lipgloss.Height(helpView)
+		if model.message != "" {
+			reserved++
+		}
+
+		if !lay.wide {
+			reserved += lipgloss.Height(calendar)
+		}
 
-		// FIXME: Need to rice minimal height of panels so that they are no smaller than calendar or
-		// make calendar size based on current windows size.
-		view = lipgloss.JoinHorizontal(lipgloss.Center, view, RenderCalendar(selectedTask))
+		listHeight = max(model.height-reserved, minListHeight)
 	}
 
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	// FIXME: This is not aligned with calendar days labels and look not aesthetic
-	// will need to play with joining so that it is aligned.
-	view = lipgloss.JoinVertical(lipgloss.Left,
-		labelStyle.Render(fmt.Sprintf("%d %s %d", time.Now().Day(), time.Now().Month().String(), time.Now().Year())),
-		view)
+	lines, scrolled := scrollLines(lines, cursorFirst, cursorLast, listHeight)
+	height := max(len(lines)+1, minListHeight)
 
-	helpView := model.help.View(model.keys)
-	view += "\n" + helpView
+	panels := lipgloss.JoinHorizontal(lipgloss.Top,
+		createUpperTextPanelBox(title+scrolled+"\n"+strings.Join(lines, "\n"), height, lay.boxWidth),
+		createDescriptionBox(description, height, lay.boxWidth, descriptionSelected))
 
-	return view
-}
+	if lowerPanel != "" {
+		panels = lipgloss.JoinVertical(lipgloss.Left, panels, lowerPanel)
+	}
+
+	// calendar header row is in line with top border of the boxes
+	view := lipgloss.JoinHorizontal(lipgloss.Top, panels, calendar)
+	if !lay.wide {
+		view = lipgloss.JoinVertical(lipgloss.Left, panels, calendar)
+	}
 
-func getDaysInMonth(t time.Time) int {
-	const overMaxDaysInMonth = 32
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	view = lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(clock())+"  "+model.saveIndicator(), view)
 
-	return overMaxDaysInMonth - time.Date(t.Year(), t.Month(), 32, 0, 0, 0, 0, time.UTC).Day()
-}
+	if model.message != "" {
+		view += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(model.message)
+	}
 
-func getDaysInYear(t time.Time) int {
-	return time.Date(t.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	view += "\n" + helpView
+
+	return view
 }
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+)
+
+// monthLayout is format in which month to jump to is typed.
+const monthLayout = "2006-01"
+
+// shownMonth returns day in month shown in calendar which is
+// current month unless user paged to another one.
+func (model Model) shownMonth(task habit.Task) time.Time {
+	if model.calendarMonth.IsZero() {
+		return task.Now()
+	}
+
+	return model.calendarMonth
+}
+
+// calendarRange returns first and last day shown in calendar or year view.
+func (model Model) calendarRange(task habit.Task) (time.Time, time.Time) {
+	month := model.shownMonth(task)
+	last := lastOfMonth(month)
+
+	if model.yearView {
+		if today := dateOf(task.Now()); last.After(today) {
+			last = today
+		}
+
+		return yearStart(last), last
+	}
+
+	return firstOfMonth(month), last
+}
+
+// showMonth shows month of given day in calendar. Months after current
+// one are not shown and calendar cursor is moved into shown month.
+func (model Model) showMonth(month time.Time) Model {
+	task := model.tasks[model.cursorRow]
+	today := task.Now()
+
+	model.calendarMonth = firstOfMonth(month)
+	if !model.calendarMonth.Before(firstOfMonth(today)) {
+		model.calendarMonth = time.Time{}
+	}
+
+	if model.calendarMode {
+		model.calendarCursor = model.clampCursor(task, model.calendarCursor)
+	}
+
+	return model
+}
+
+// pageMonth shows month delta months away from shown one.
+func (model Model) pageMonth(delta int) Model {
+	return model.showMonth(firstOfMonth(model.shownMonth(model.tasks[model.cursorRow])).AddDate(0, delta, 0))
+}
+
+// jumpToMonth shows month typed as YYYY-MM.
+func (model Model) jumpToMonth(text string) (Model, error) {
+	month, err := time.ParseInLocation(monthLayout, text, model.tasks[model.cursorRow].Now().Location())
+	if err != nil {
+		return model, fmt.Errorf("invalid month %q expected YYYY-MM: %w", text, err)
+	}
+
+	return model.showMonth(month), nil
+}
+
+// clampCursor returns calendar cursor day moved into shown
+// range of days and not after today keeping time of today.
+func (model Model) clampCursor(task habit.Task, cursor time.Time) time.Time {
+	today := task.Now()
+	day := dateOf(cursor)
+
+	first, last := model.calendarRange(task)
+	if last.After(dateOf(today)) {
+		last = dateOf(today)
+	}
+
+	switch {
+	case day.Before(first):
+		day = first
+	case day.After(last):
+		day = last
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		today.Hour(), today.Minute(), today.Second(), today.Nanosecond(), today.Location())
+}
+
+// calendarView renders month calendar or year view of selected task.
+func (model Model) calendarView(task habit.Task) string {
+	cursor := time.Time{}
+	if model.calendarMode {
+		cursor = model.calendarCursor
+	}
+
+	if model.yearView {
+		_, last := model.calendarRange(task)
+
+		return renderYear(task, last, cursor)
+	}
+
+	return renderCalendar(task, model.shownMonth(task), cursor)
+}
@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+const (
+	// trendPeriods is number of weeks and months shown in sparklines.
+	trendPeriods = 12
+	// barWidth is width of the longest bar in bar charts.
+	barWidth = 20
+	// shownStrikes is number of the latest strikes listed in strike history.
+	shownStrikes = 6
+)
+
+// sparkLevels are characters of sparkline from the lowest rate.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█") //nolint:gochecknoglobals
+
+// sparkline returns line with character per period which height follows
+// its completion rate. Periods without expected completions are blank.
+func sparkline(rates []habit.PeriodRate) string {
+	line := make([]rune, 0, len(rates))
+
+	for _, rate := range rates {
+		if rate.Expected == 0 {
+			line = append(line, ' ')
+
+			continue
+		}
+
+		level := int(min(rate.Rate(), 1) * float64(len(sparkLevels)-1))
+		line = append(line, sparkLevels[level])
+	}
+
+	return string(line)
+}
+
+// totalRate returns completion rate of all periods together as percentage.
+func totalRate(rates []habit.PeriodRate) int {
+	total := habit.PeriodRate{}
+	for _, rate := range rates {
+		total.Completed += rate.Completed
+		total.Expected += rate.Expected
+	}
+
+	return percent(total.Rate())
+}
+
+func percent(rate float64) int {
+	return int(min(rate, 1)*100 + 0.5) //nolint:gomnd
+}
+
+// bar returns horizontal bar of value scaled so that maxValue fills barWidth.
+func bar(value, maxValue int) string {
+	if maxValue == 0 {
+		return ""
+	}
+
+	return strings.Repeat("█", (value*barWidth+maxValue-1)/maxValue)
+}
+
+// taskStats renders charts of task completion history.
+func taskStats(task habit.Task) string {
+	title := lipgloss.NewStyle().Bold(true)
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	spark := lipgloss.NewStyle().Foreground(lipgloss.Color("#01BE85"))
+	rateName := "Completion"
+	countName := "Completions"
+
+	if task.Kind == habit.TaskKindQuit {
+		rateName = "Clean days"
+		countName = "Relapses"
+	}
+
+	weeks := task.WeeklyRates(trendPeriods)
+	months := task.MonthlyRates(trendPeriods)
+	lines := []string{
+		title.Render("Statistics: " + task.Name),
+		"",
+		title.Render(rateName + " rate"),
+		fmt.Sprintf("%s %s %3d%%", label.Render("Last 12 weeks: "), spark.Render(sparkline(weeks)), totalRate(weeks)),
+		fmt.Sprintf("%s %s %3d%%", label.Render("Last 12 months:"), spark.Render(sparkline(months)), totalRate(months)),
+		"",
+		title.Render(countName + " by week day"),
+	}
+
+	weekdays := task.WeekdayCompletions()
+	most := 0
+
+	for _, count := range weekdays {
+		most = max(most, count)
+	}
+
+	for idx, count := range weekdays {
+		day := time.Weekday((idx + 1) % weekDays).String()[:3]
+		lines = append(lines, fmt.Sprintf("%s %s %d", label.Render(day), spark.Width(barWidth).Render(bar(count, most)), count))
+	}
+
+	lines = append(lines, "", title.Render("Strike history"))
+
+	runs := task.StrikeRuns()
+	if len(runs) == 0 {
+		lines = append(lines, label.Render("No strikes yet."))
+	}
+
+	longest := 0
+	for _, run := range runs {
+		longest = max(longest, run.Length)
+	}
+
+	for _, run := range runs[max(0, len(runs)-shownStrikes):] {
+		lines = append(lines, fmt.Sprintf("%s %s %d",
+			label.Render(run.Start.Format(time.DateOnly)), spark.Render(bar(run.Length, longest)), run.Length))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// overviewTable renders table comparing all shown habits with
+// selected task highlighted.
+func (model Model) overviewTable(selected int) string {
+	rows := [][]string{}
+	indexes := []int{}
+
+	for _, idx := range model.statsTasks() {
+		task := model.tasks[idx]
+		indexes = append(indexes, idx)
+		rows = append(rows, []string{
+			task.Name,
+			strconv.Itoa(percent(task.MonthlyRates(1)[0].Rate())) + "%",
+			strconv.Itoa(task.CurrentStrike()),
+			strconv.Itoa(task.BestStrike()),
+		})
+	}
+
+	header := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Padding(0, 1)
+	cell := lipgloss.NewStyle().Padding(0, 1)
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("Habit", "Month", "Strike", "Best").
+		Rows(rows...).
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return header
+			}
+
+			if indexes[row] == selected {
+				return cell.Reverse(true)
+			}
+
+			return cell
+		}).
+		Render()
+}
+
+// statsView renders stats screen of selected task and all habits
+// overview put side by side when they fit terminal width.
+func (model Model) statsView() string {
+	if !model.hasSelection() {
+		return "No habits."
+	}
+
+	box := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(0, 1)
+	charts := box.Render(taskStats(model.tasks[model.cursorRow]))
+	overview := model.overviewTable(model.cursorRow)
+
+	if model.width == 0 || lipgloss.Width(charts)+lipgloss.Width(overview) <= model.width {
+		return lipgloss.JoinHorizontal(lipgloss.Top, charts, overview)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, charts, overview)
+}
+
+// updateStatsMode handles keys of stats screen in which cursor
+// selects habit which charts are shown.
+func (model Model) updateStatsMode(msg tea.KeyMsg) Model {
+	switch {
+	case key.Matches(msg, model.keys.Quit), key.Matches(msg, model.keys.Stats):
+		model.statsMode = false
+		model = model.refreshList()
+	case key.Matches(msg, model.keys.Up):
+		model.cursorRow = model.nextTask(-1)
+	case key.Matches(msg, model.keys.Down):
+		model.cursorRow = model.nextTask(1)
+	case key.Matches(msg, model.keys.Help):
+		model.help.ShowAll = !model.help.ShowAll
+	}
+
+	return model
+}
+
+// statsTasks returns indexes of tasks in list order including
+// tasks of collapsed categories.
+func (model Model) statsTasks() []int {
+	model.collapsed = map[string]struct{}{}
+	tasks := []int{}
+
+	for _, row := range model.listRows() {
+		if !row.header {
+			tasks = append(tasks, row.task)
+		}
+	}
+
+	return tasks
+}
+
+// nextTask returns index of task shown delta tasks away from cursor in stats screen.
+func (model Model) nextTask(delta int) int {
+	tasks := model.statsTasks()
+	if len(tasks) == 0 {
+		return model.cursorRow
+	}
+
+	current := max(0, slices.Index(tasks, model.cursorRow))
+
+	return tasks[max(0, min(current+delta, len(tasks)-1))]
+}
@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// watchInterval is how often running TUI checks whether tasks were
+	// changed by other habitui.
+	watchInterval = 2 * time.Second
+	// saveDelay is how long TUI waits after change before tasks are
+	// saved so that quick changes are saved together.
+	saveDelay = time.Second
+)
+
+// saveState tells whether shown tasks are saved in storage.
+type saveState int
+
+const (
+	saveStateSaved saveState = iota
+	saveStateUnsaved
+	saveStateFailed
+)
+
+// watchMsg is sent every watchInterval when model has storage.
+type watchMsg time.Time
+
+// saveDueMsg is sent saveDelay after change that made count of changes
+// in history equal to changes.
+type saveDueMsg struct {
+	changes int
+}
+
+// savedMsg reports result of saving tasks in the background. Sent are
+// tasks that were saved and tasks are tasks merged by storage.
+type savedMsg struct {
+	changes int
+	sent    habit.TaskList
+	tasks   habit.TaskList
+	err     error
+}
+
+// reloadedMsg reports result of reloading sent tasks in the background.
+type reloadedMsg struct {
+	sent    habit.TaskList
+	tasks   habit.TaskList
+	changed bool
+	err     error
+}
+
+// Storage saves tasks shown in TUI and reloads them when they are changed
+// outside of it. Both methods merge tasks with stored changes and return
+// merged tasks, Reload reports whether stored tasks have changed.
+// Save is called from background so it has to be safe to call with Reload.
+type Storage interface {
+	Save(tasks habit.TaskList) (habit.TaskList, error)
+	Reload(tasks habit.TaskList) (habit.TaskList, bool, error)
+}
+
+func watch() tea.Cmd {
+	return tea.Tick(watchInterval, func(t time.Time) tea.Msg { return watchMsg(t) })
+}
+
+// WithStorage returns model that reloads tasks changed in storage by other
+// habitui and if autosave is set saves tasks shortly after each change
+// and each time day changes.
+func (model Model) WithStorage(storage Storage, autosave bool) Model {
+	model.storage = storage
+	model.autosave = autosave
+
+	return model
+}
+
+// updateWatch starts reloading tasks in background to check whether
+// they were changed in storage.
+func (model Model) updateWatch() (Model, tea.Cmd) {
+	// tasks are not replaced while they are edited or saved
+	if model.editEnabled || model.deleting != "" || model.saving || model.reloading {
+		return model, watch()
+	}
+
+	model.reloading = true
+	storage, sent := model.storage, model.tasks.Clone()
+
+	return model, func() tea.Msg {
+		tasks, changed, err := storage.Reload(sent.Clone())
+
+		return reloadedMsg{sent: sent, tasks: tasks, changed: changed, err: err}
+	}
+}
+
+// updateReloaded shows tasks changed in storage merged with changes made
+// while they were reloaded and saves tasks that waited for reload to end.
+func (model Model) updateReloaded(msg reloadedMsg) (Model, tea.Cmd) {
+	model.reloading = false
+
+	switch {
+	case errors.Is(msg.err, habit.ErrConflict):
+		model.conflict = msg.err
+	case msg.err != nil:
+		model.message = "failed to reload tasks: " + msg.err.Error()
+	case msg.changed:
+		model.message = "reloaded tasks changed by other habitui"
+	}
+
+	if msg.changed {
+		model = model.merge(msg.sent, msg.tasks)
+	}
+
+	if model.conflict != nil {
+		model.saveState = saveStateFailed
+		model.message = "tasks were changed by other habitui, " + model.conflict.Error()
+	}
+
+	if model.quitting {
+		return model, tea.Quit
+	}
+
+	if model.autosave && model.saveState == saveStateUnsaved {
+		saving, cmd := model.save()
+
+		return saving, tea.Batch(watch(), cmd)
+	}
+
+	return model, watch()
+}
+
+// merge shows tasks returned by storage for sent tasks merged with changes
+// made since they were sent. Storage already took returned tasks as stored
+// so conflicting changes can not be saved any more. Undo history of tasks
+// changed by others is dropped so that undo keeps their changes.
+func (model Model) merge(sent, stored habit.TaskList) Model {
+	merged, conflicts := habit.MergeTasks(sent, model.tasks, stored)
+	model.history.rebase(changedTasks(model.tasks, merged))
+	model.tasks = merged
+
+	if len(conflicts) > 0 && model.conflict == nil {
+		model.conflict = fmt.Errorf("%w in %s", habit.ErrConflict, strings.Join(conflicts, ", "))
+	}
+
+	return model.afterHistoryChange()
+}
+
+// Conflict returns error wrapping habit.ErrConflict when tasks were changed
+// differently in storage so they are no longer saved to not overwrite
+// other changes, it is nil otherwise.
+func (model Model) Conflict() error {
+	return model.conflict
+}
+
+// afterChange marks tasks unsaved when update changed them and with
+// autosave schedules saving them after saveDelay.
+func (model Model) afterChange(changes int, cmd tea.Cmd) (Model, tea.Cmd) {
+	if model.storage == nil || model.history.changes == changes {
+		return model, cmd
+	}
+
+	model.saveState = saveStateUnsaved
+
+	if !model.autosave {
+		return model, cmd
+	}
+
+	changes = model.history.changes
+
+	return model, tea.Batch(cmd, tea.Tick(saveDelay, func(time.Time) tea.Msg { return saveDueMsg{changes: changes} }))
+}
+
+// updateSaveDue starts saving tasks unless they were changed again
+// since save was scheduled and the later change will save them.
+func (model Model) updateSaveDue(msg saveDueMsg) (Model, tea.Cmd) {
+	if msg.changes != model.history.changes {
+		return model, nil
+	}
+
+	return model.save()
+}
+
+// save starts saving tasks in background, tasks are saved again once
+// saving ends if they were changed in meantime.
+func (model Model) save() (Model, tea.Cmd) {
+	if model.storage == nil || model.saving || model.reloading {
+		return model, nil
+	}
+
+	if model.conflict != nil {
+		model.saveState = saveStateFailed
+		model.message = "tasks are not saved, " + model.conflict.Error()
+
+		return model, nil
+	}
+
+	model.saving = true
+	storage, changes, sent := model.storage, model.history.changes, model.tasks.Clone()
+
+	return model, func() tea.Msg {
+		tasks, err := storage.Save(sent.Clone())
+
+		return savedMsg{changes: changes, sent: sent, tasks: tasks, err: err}
+	}
+}
+
+// updateSaved shows result of saving tasks. Changes of other habitui
+// merged by storage are merged with changes made while tasks were saved.
+func (model Model) updateSaved(msg savedMsg) (Model, tea.Cmd) {
+	model.saving = false
+
+	switch {
+	case errors.Is(msg.err, habit.ErrConflict):
+		model.conflict = msg.err
+	case msg.err != nil:
+		model.saveState = saveStateFailed
+		model.message = "failed to save tasks: " + msg.err.Error()
+	default:
+		model = model.merge(msg.sent, msg.tasks)
+	}
+
+	if model.conflict != nil {
+		model.saveState = saveStateFailed
+		model.message = "tasks are not saved, they were changed by other habitui, " + model.conflict.Error()
+	}
+
+	switch {
+	case model.quitting:
+		return model, tea.Quit
+	case msg.err != nil || model.conflict != nil:
+		return model, nil
+	case msg.changes != model.history.changes:
+		return model.save()
+	}
+
+	model.saveState = saveStateSaved
+
+	return model, nil
+}
+
+// saveIndicator returns whether tasks are saved for view,
+// it is empty when model has no storage.
+func (model Model) saveIndicator() string {
+	if model.storage == nil {
+		return ""
+	}
+
+	switch model.saveState {
+	case saveStateUnsaved:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("● unsaved")
+	case saveStateFailed:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✗ sync failed")
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Render("✓ saved")
+	}
+}
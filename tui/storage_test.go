@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bazko1/habitui/habit"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeStorage returns stored tasks on reload and keeps saved ones.
+type fakeStorage struct {
+	stored  habit.TaskList
+	changed bool
+	err     error
+	saves   int
+}
+
+func (storage *fakeStorage) Save(tasks habit.TaskList) (habit.TaskList, error) {
+	if storage.err != nil {
+		return nil, storage.err
+	}
+
+	storage.saves++
+	storage.stored = tasks.Clone()
+
+	return tasks, nil
+}
+
+func (storage *fakeStorage) Reload(tasks habit.TaskList) (habit.TaskList, bool, error) {
+	if storage.err != nil || !storage.changed {
+		return tasks, false, storage.err
+	}
+
+	return storage.stored.Clone(), true, nil
+}
+
+func update(t *testing.T, model Model, msg tea.Msg) (Model, tea.Cmd) {
+	t.Helper()
+
+	updated, cmd := model.Update(msg)
+
+	return updated.(Model), cmd //nolint:forcetypeassert
+}
+
+var space = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}} //nolint:gochecknoglobals
+
+func TestModelAutosave(t *testing.T) {
+	t.Parallel()
+
+	storage := &fakeStorage{}
+	model := NewTuiModel(habit.TaskList{habit.NewTask("run", "")}).WithStorage(storage, true)
+
+	model, cmd := update(t, model, space)
+	if model.saveState != saveStateUnsaved || cmd == nil {
+		t.Fatal("Change should mark tasks unsaved and schedule save")
+	}
+
+	// save scheduled before the last change waits for later one
+	model, _ = update(t, model, space)
+	if model, cmd = update(t, model, saveDueMsg{changes: model.history.changes - 1}); cmd != nil || model.saving {
+		t.Fatal("Save scheduled before the last change should be skipped")
+	}
+
+	model, cmd = update(t, model, saveDueMsg{changes: model.history.changes})
+	if !model.saving || cmd == nil {
+		t.Fatal("Save should start once it is due")
+	}
+
+	model, _ = update(t, model, cmd())
+	if model.saving || model.saveState != saveStateSaved || storage.saves != 1 {
+		t.Fatalf("Tasks should be saved once got state %d and %d saves", model.saveState, storage.saves)
+	}
+
+	storage.err = errors.New("server is down")
+	model, _ = update(t, model, space)
+	model, cmd = update(t, model, saveDueMsg{changes: model.history.changes})
+	model, _ = update(t, model, cmd())
+
+	if model.saveState != saveStateFailed {
+		t.Fatalf("Failed save should be shown got state %d", model.saveState)
+	}
+}
+
+func TestModelReload(t *testing.T) {
+	t.Parallel()
+
+	run, read := habit.NewTask("run", ""), habit.NewTask("read", "")
+	storage := &fakeStorage{}
+	model := NewTuiModel(habit.TaskList{run}).WithStorage(storage, false)
+
+	model, cmd := update(t, model, watchMsg(time.Now()))
+	if !model.reloading || cmd == nil {
+		t.Fatal("Watch should reload tasks in background")
+	}
+
+	// other habitui added read while run is completed here
+	storage.stored, storage.changed = habit.TaskList{run, read}, true
+	reloaded := cmd()
+	model, _ = update(t, model, space)
+	model, _ = update(t, model, reloaded)
+
+	if len(model.tasks) != 2 || !model.tasks[0].WasCompletedToday() || model.Conflict() != nil {
+		t.Fatalf("Reloaded tasks should be merged with change made meanwhile got %v", model.tasks)
+	}
+
+	storage.err = habit.ErrConflict
+	model, cmd = update(t, model, watchMsg(time.Now()))
+	model, _ = update(t, model, cmd())
+
+	if !errors.Is(model.Conflict(), habit.ErrConflict) || model.saveState != saveStateFailed {
+		t.Fatal("Conflict found by reload should be shown")
+	}
+
+	// conflicting tasks are not saved
+	storage.err = nil
+	if _, cmd := model.save(); cmd != nil || storage.saves != 0 {
+		t.Fatal("Tasks should not be saved after conflict")
+	}
+}
+
+func TestModelUndoAfterReload(t *testing.T) {
+	t.Parallel()
+
+	storage := &fakeStorage{}
+	model := NewTuiModel(habit.TaskList{habit.NewTask("run", "")}).WithStorage(storage, false)
+	model, _ = update(t, model, space)
+
+	for _, msg := range append(append(typed("V"), typed("2024-01-01")...), tea.KeyMsg{Type: tea.KeyEnter}) {
+		model, _ = update(t, model, msg)
+	}
+
+	before := tasksJSON(t, model.tasks)
+
+	// other habitui added read after run was changed here
+	model, cmd := update(t, model, watchMsg(time.Now()))
+	storage.stored, storage.changed = append(model.tasks.Clone(), habit.NewTask("read", "")), true
+	model, _ = update(t, model, cmd())
+
+	model, _ = update(t, model, typed("u")[0])
+	model, _ = update(t, model, typed("u")[0])
+
+	if len(model.tasks) != 2 || model.tasks[1].Name != "read" {
+		t.Fatalf("Undo should keep task added by other habitui got %v", model.tasks)
+	}
+
+	if model.tasks[0].WasCompletedToday() || len(model.tasks[0].Vacations()) != 0 {
+		t.Fatal("Undo should revert changes of run")
+	}
+
+	model, _ = update(t, model, tea.KeyMsg{Type: tea.KeyCtrlR})
+	model, _ = update(t, model, tea.KeyMsg{Type: tea.KeyCtrlR})
+
+	if got := tasksJSON(t, model.tasks[:1]); got != before {
+		t.Fatalf("Redo should apply changes of run again\n%s\ngot\n%s", before, got)
+	}
+
+	// other habitui changed run so its changes made here can not be undone
+	model, cmd = update(t, model, watchMsg(time.Now()))
+	storage.stored = model.tasks.Clone()
+	storage.stored[0].Description = "5 km"
+	model, _ = update(t, model, cmd())
+
+	if model, _ = update(t, model, typed("u")[0]); model.message != "nothing to undo" ||
+		model.tasks[0].Description != "5 km" || !model.tasks[0].WasCompletedToday() {
+		t.Fatalf("Undo should not revert run changed by other habitui got %q", model.message)
+	}
+}
+
+func TestModelSaveConflict(t *testing.T) {
+	t.Parallel()
+
+	storage := &fakeStorage{}
+	model := NewTuiModel(habit.TaskList{habit.NewTask("run", "")}).WithStorage(storage, true)
+
+	model, _ = update(t, model, space)
+	model, cmd := update(t, model, saveDueMsg{changes: model.history.changes})
+	sent := model.tasks.Clone()
+
+	// other habitui changed run saved with tasks while it is changed here again
+	stored := sent.Clone()
+	stored[0].Description = "5 km"
+	cmd()
+	model, _ = update(t, model, space)
+	model, cmd = update(t, model, savedMsg{changes: model.history.changes - 1, sent: sent, tasks: stored})
+
+	if !errors.Is(model.Conflict(), habit.ErrConflict) || model.saveState != saveStateFailed || cmd != nil {
+		t.Fatalf("Task changed by both should be shown as conflict and not saved got %v", model.Conflict())
+	}
+}